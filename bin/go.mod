@@ -7,7 +7,9 @@ require (
 	github.com/go-kit/kit v0.10.0
 	github.com/google/go-github/v29 v29.0.3
 	github.com/maxbrunsfeld/counterfeiter/v6 v6.2.3
+	github.com/pmezard/go-difflib v1.0.0
 	github.com/stretchr/testify v1.5.1
 	golang.org/x/oauth2 v0.0.0-20190226205417-e64efc72b421
 	gopkg.in/alecthomas/kingpin.v2 v2.2.6
+	gopkg.in/yaml.v3 v3.0.1
 )
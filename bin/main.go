@@ -1,8 +1,14 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
@@ -11,24 +17,206 @@ import (
 	"gopkg.in/alecthomas/kingpin.v2"
 
 	"github.com/jace-ys/mobydick-action/bin/pkg/action"
+	"github.com/jace-ys/mobydick-action/bin/pkg/config"
 	"github.com/jace-ys/mobydick-action/bin/pkg/worker"
 )
 
 var (
 	actionCmd    = kingpin.New("action", "Command-line interface for managing this GitHub Action.")
-	organisation = actionCmd.Flag("organisation", "Name of organisation in GitHub.").Required().String()
-	token        = actionCmd.Flag("token", "Token used for authenticating with GitHub.").Required().String()
-
-	distributeCmd = actionCmd.Command("distribute", "Distribute this GitHub Action to all repositories in the organisation.")
-	concurrency   = distributeCmd.Flag("concurrency", "Size of worker pool to perform concurrent work.").Default("5").Int()
-	file          = distributeCmd.Flag("file", "Workflow file to commit into repositories.").Default("mobydick.yaml").String()
-	version       = distributeCmd.Flag("version", "Version of this GitHub Action to distribute.").Default("v1.0.0").String()
-	private       = distributeCmd.Flag("private", "Only distribute this GitHub Action to private repositories.").Default("false").Bool()
-	dryRun        = distributeCmd.Flag("dry-run", "Perform a dry run, showing all the repositories that will be committed to.").Default("false").Bool()
+	organisation = actionCmd.Flag("organisation", "Name of organisation in GitHub.").String()
+	token        = actionCmd.Flag("token", "Token used for authenticating with GitHub.").String()
+	_            = actionCmd.Flag(config.ConfigFlag, "Configuration file supplying default flag values, looked up in the working directory if not given.").PlaceHolder(config.DefaultFile).String()
+	_            = actionCmd.Flag(config.ProfileFlag, "Named profile in the configuration file to take default flag values from.").String()
+
+	distributeCmd      = actionCmd.Command("distribute", "Distribute this GitHub Action to all repositories in the organisation.")
+	distributeTemplate = newTemplateFlags(distributeCmd)
+	concurrency        = distributeCmd.Flag("concurrency", "Size of worker pool to perform concurrent work.").Default("5").Int()
+	distributeFilter   = newFilterFlags(distributeCmd)
+	rateLimit          = distributeCmd.Flag("rate-limit", "Number of repositories to start distributing to a second, slowed down further when the GitHub rate limit runs low, or 0 for no limit.").Default("0").Float64()
+	rateBurst          = distributeCmd.Flag("rate-burst", "Number of repositories that may be started at once within --rate-limit.").Default("1").Int()
+	order              = distributeCmd.Flag("order", "Order to distribute to repositories in: as listed, by name, most recently pushed first, or most starred last.").Default(action.OrderListed).Enum(action.Orders...)
+	breakerFailures    = distributeCmd.Flag("breaker-consecutive-failures", "Number of repositories failing in a row after which the rest are aborted, or 0 to never abort.").Default("0").Int()
+	breakerRatio       = distributeCmd.Flag("breaker-failure-ratio", "Ratio of failures among the last --breaker-window repositories after which the rest are aborted, or 0 to never abort.").Default("0").Float64()
+	breakerWindow      = distributeCmd.Flag("breaker-window", "Number of most recent repositories that --breaker-failure-ratio is measured over.").Default("20").Int()
+	withDependabot     = distributeCmd.Flag("with-dependabot", "Also commit a Dependabot config with a docker entry for each directory containing Dockerfiles.").Default("false").Bool()
+	dependabotSchedule = distributeCmd.Flag("dependabot-schedule", "Update schedule for the docker entries in the Dependabot config.").Default("weekly").Enum(action.DependabotIntervals...)
+	pullRequest        = distributeCmd.Flag("pull-request", "Commit to a separate branch and open a pull request instead of committing to the default branch.").Default("false").Bool()
+	branch             = distributeCmd.Flag("branch", "Branch to commit to instead of the default branch, or to open pull requests from with --pull-request.").String()
+	commitMessage      = distributeCmd.Flag("commit-message", "Message for the commits made to each repository, templated with .Organisation, .Repository and .Version.").Default(action.DefaultCommitMessage).String()
+	authorName         = distributeCmd.Flag("author-name", "Name of the author of the commits, left to GitHub to fill in if not given.").String()
+	authorEmail        = distributeCmd.Flag("author-email", "Email of the author of the commits, left to GitHub to fill in if not given.").String()
+	committerName      = distributeCmd.Flag("committer-name", "Name of the committer of the commits, left to GitHub to fill in if not given.").String()
+	committerEmail     = distributeCmd.Flag("committer-email", "Email of the committer of the commits, left to GitHub to fill in if not given.").String()
+	signingKey         = distributeCmd.Flag("signing-key", "ID of the GPG key, or path to the SSH private key, to sign commits with.").String()
+	signingFormat      = distributeCmd.Flag("signing-format", "Format of the signing key.").Default(action.SigningFormatGPG).Enum(action.SigningFormats...)
+	requireSigning     = distributeCmd.Flag("require-signing", "Fail instead of falling back to unsigned commits when commits can't be signed and verified.").Default("false").Bool()
+	dryRun             = distributeCmd.Flag("dry-run", "Perform a dry run, printing a diff of the changes that would be committed to each repository.").Default("false").Bool()
+	injectInto         = distributeCmd.Flag("inject-into", "Inject the jobs of the workflow into the first existing workflow matching this file name glob instead of committing a new workflow file.").PlaceHolder("GLOB").String()
+	injectJob          = distributeCmd.Flag("inject-job", "Inject only the steps using this GitHub Action into this job of the workflow matched by --inject-into.").String()
+	allowDuplicates    = distributeCmd.Flag("allow-duplicates", "Commit to repositories that already use this GitHub Action in another workflow instead of skipping them.").Default("false").Bool()
+	reportFile         = distributeCmd.Flag("report", "File to write a JSON report of the changes made to each repository to.").String()
+	waves              = distributeCmd.Flag("waves", "Roll out in waves, each reaching this cumulative percentage of repositories, such as 1%,10%,50%,100%.").String()
+	waveSeed           = distributeCmd.Flag("wave-seed", "Seed for the order repositories are rolled out in, which is the same for the same seed.").String()
+	waveDelay          = distributeCmd.Flag("wave-delay", "Time to wait between waves instead of asking for confirmation.").Duration()
+	verifyRuns         = distributeCmd.Flag("verify-runs", "Wait for the workflow runs triggered by each commit and report whether they passed. Runs that don't pass count as failures for the circuit breaker.").Default("false").Bool()
+	runTimeout         = distributeCmd.Flag("run-timeout", "Time to wait for the workflow runs triggered by each commit to complete.").Default(action.DefaultRunTimeout.String()).Duration()
+	maxRunFailureRatio = distributeCmd.Flag("max-run-failure-ratio", "Ratio of workflow runs in a wave that may fail or time out before the remaining waves are aborted.").Default("0").Float64()
+	maxFailureRatio    = distributeCmd.Flag("max-failure-ratio", "Ratio of repositories in a wave that may fail before the remaining waves are aborted.").Default("0").Float64()
+
+	coverageCmd         = actionCmd.Command("coverage", "Audit how well the Dependabot config of each repository covers its Dockerfiles.")
+	coverageConcurrency = coverageCmd.Flag("concurrency", "Size of worker pool to perform concurrent work.").Default("5").Int()
+	coverageFilter      = newFilterFlags(coverageCmd)
+
+	driftCmd         = actionCmd.Command("drift", "Compare the workflow installed in each repository with the workflow rendered for it from the template.")
+	driftTemplate    = newTemplateFlags(driftCmd)
+	driftConcurrency = driftCmd.Flag("concurrency", "Size of worker pool to perform concurrent work.").Default("5").Int()
+	driftFilter      = newFilterFlags(driftCmd)
+	maxDrifted       = driftCmd.Flag("max-drifted", "Number of drifted repositories to allow before exiting with an error.").Default("0").Int()
+
+	listCmd    = actionCmd.Command("list", "List the repositories in the organisation that the filters select, without changing anything.")
+	listFilter = newFilterFlags(listCmd)
+	listOutput = listCmd.Flag("output", "Format to list the repositories in: a table, JSON or only their names.").Default(action.ListFormatTable).Enum(action.ListFormats...)
+
+	lintTemplateCmd = actionCmd.Command("lint-template", "Render the workflow file for a sample repository and validate it as a GitHub Actions workflow.")
+	lintTemplate    = newTemplateFlags(lintTemplateCmd)
+
+	templateCmd     = actionCmd.Command("template", "Manage the workflow template.")
+	templateShowCmd = templateCmd.Command("show", "Print the built-in workflow template, as a starting point for custom templates.")
 )
 
+type filterFlags struct {
+	private          *bool
+	skipArchived     *bool
+	skipForks        *bool
+	repositories     *[]string
+	repositoriesFrom *string
+	team             *string
+	codeowner        *string
+}
+
+func newFilterFlags(cmd *kingpin.CmdClause) *filterFlags {
+	return &filterFlags{
+		private:          cmd.Flag("private", "Only select private repositories.").Default("false").Bool(),
+		skipArchived:     cmd.Flag("skip-archived", "Leave out archived repositories.").Default("false").Bool(),
+		skipForks:        cmd.Flag("skip-forks", "Leave out forked repositories.").Default("false").Bool(),
+		repositories:     cmd.Flag("repository", "Only select the repository with this name. Can be repeated.").Strings(),
+		repositoriesFrom: cmd.Flag("repositories-from", "Only select the repositories named on each line of this file, or standard input if -.").PlaceHolder("FILE").String(),
+		team:             cmd.Flag("team", "Only select the repositories of the team with this slug.").String(),
+		codeowner:        cmd.Flag("codeowner", "Only select repositories whose CODEOWNERS file assigns their Dockerfiles to this owner, such as @org/team.").String(),
+	}
+}
+
+func (f *filterFlags) load() (action.RepositoryFilter, error) {
+	filter := action.RepositoryFilter{
+		Private:      *f.private,
+		SkipArchived: *f.skipArchived,
+		SkipForks:    *f.skipForks,
+		Names:        *f.repositories,
+		Team:         *f.team,
+		Codeowner:    *f.codeowner,
+	}
+
+	if *f.repositoriesFrom != "" {
+		var data []byte
+		var err error
+		if *f.repositoriesFrom == "-" {
+			data, err = ioutil.ReadAll(os.Stdin)
+		} else {
+			data, err = ioutil.ReadFile(*f.repositoriesFrom)
+		}
+		if err != nil {
+			return filter, err
+		}
+
+		for _, line := range strings.Split(string(data), "\n") {
+			name := strings.TrimSpace(line)
+			if name != "" && !strings.HasPrefix(name, "#") {
+				filter.Names = append(filter.Names, name)
+			}
+		}
+		if len(filter.Names) == 0 {
+			return filter, fmt.Errorf("no repositories named in %s", *f.repositoriesFrom)
+		}
+	}
+
+	return filter, nil
+}
+
+type templateFlags struct {
+	file       *string
+	dest       *string
+	version    *string
+	pinSHA     *bool
+	leftDelim  *string
+	rightDelim *string
+	overrides  *string
+}
+
+func newTemplateFlags(cmd *kingpin.CmdClause) *templateFlags {
+	return &templateFlags{
+		file:       cmd.Flag("file", "Custom workflow template to commit into repositories instead of the built-in one.").String(),
+		dest:       cmd.Flag("dest", "Path to commit the workflow file to, defaulting to the template's base name under .github/workflows/.").String(),
+		version:    cmd.Flag("version", "Version of this GitHub Action to distribute, or latest for its newest release.").Default(action.LatestVersion).String(),
+		pinSHA:     cmd.Flag("pin-sha", "Render the commit SHA of the version as the ref to use this GitHub Action by.").Default("false").Bool(),
+		leftDelim:  cmd.Flag("left-delim", "Left delimiter for template actions in the workflow template given by --file.").Default("{{").String(),
+		rightDelim: cmd.Flag("right-delim", "Right delimiter for template actions in the workflow template given by --file.").Default("}}").String(),
+		overrides:  cmd.Flag("overrides", "File of template values to override for matching repositories.").String(),
+	}
+}
+
+func (f *templateFlags) load() (*action.WorkflowTemplate, error) {
+	// The built-in template is written with the default delimiters, whatever the flags say
+	name, text, delimiters := action.DefaultTemplateName, action.DefaultTemplate, action.Delimiters{}
+	if *f.file != "" {
+		var err error
+		name = *f.file
+		delimiters = action.Delimiters{Left: *f.leftDelim, Right: *f.rightDelim}
+		text, err = ioutil.ReadFile(*f.file)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dest, err := action.WorkflowPath(*f.dest, name)
+	if err != nil {
+		return nil, err
+	}
+
+	workflowTemplate, err := action.NewWorkflowTemplate(dest, text, *f.version, delimiters)
+	if err != nil {
+		return nil, err
+	}
+
+	if *f.overrides != "" {
+		workflowTemplate.Overrides, err = action.LoadOverrides(*f.overrides)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return workflowTemplate, nil
+}
+
+// needsGitHub reports whether resolving the version requires looking up the
+// releases or tags of this action.
+func (f *templateFlags) needsGitHub() bool {
+	return *f.version == action.LatestVersion || *f.pinSHA
+}
+
+func (f *templateFlags) resolveVersion(ctx context.Context, logger log.Logger, actionManager *action.ActionManager, workflowTemplate *action.WorkflowTemplate) {
+	version, ref, err := actionManager.ResolveVersion(ctx, *f.version, *f.pinSHA)
+	exitIfError(logger, err)
+
+	workflowTemplate.Version = version
+	workflowTemplate.Ref = ref
+	level.Info(logger).Log("event", "resolve_version", "version", version, "ref", ref)
+}
+
 func main() {
-	command := kingpin.MustParse(actionCmd.Parse(os.Args[1:]))
+	args, err := config.Apply(actionCmd, os.Args[1:])
+	actionCmd.FatalIfError(err, "failed to load configuration")
+
+	command := kingpin.MustParse(actionCmd.Parse(args))
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -36,30 +224,312 @@ func main() {
 	logger := log.NewLogfmtLogger(log.NewSyncWriter(os.Stderr))
 	logger = log.With(logger, "caller", log.DefaultCaller)
 
-	workflowFile, err := action.NewWorkflowFile(*file, *version)
-	if err != nil {
-		level.Error(logger).Log("error", err)
-		os.Exit(1)
+	switch command {
+	case distributeCmd.FullCommand():
+		workflowTemplate, err := distributeTemplate.load()
+		exitIfError(logger, err)
+
+		message, err := action.NewCommitMessage(*commitMessage)
+		exitIfError(logger, err)
+
+		var poolOpts []worker.Option
+		less, err := action.JobOrder(*order)
+		exitIfError(logger, err)
+		if less != nil {
+			poolOpts = append(poolOpts, worker.WithOrder(less))
+		}
+		if *breakerFailures > 0 || *breakerRatio > 0 {
+			poolOpts = append(poolOpts, worker.WithCircuitBreaker(&worker.CircuitBreaker{
+				MaxConsecutiveFailures: *breakerFailures,
+				MaxFailureRatio:        *breakerRatio,
+				Window:                 *breakerWindow,
+			}))
+		}
+
+		var limiter *worker.RateLimiter
+		if *rateLimit > 0 {
+			limiter = worker.NewRateLimiter(*rateLimit, *rateBurst)
+			poolOpts = append(poolOpts, worker.WithRateLimiter(limiter))
+		}
+
+		workerPool := worker.NewWorkerPool(*concurrency, poolOpts...)
+		githubClient := newGitHubClient(ctx, limiter)
+
+		actionManager := action.NewActionManager(ctx, logger, *organisation, *dryRun, workflowTemplate, workerPool, newServices(githubClient))
+		distributeTemplate.resolveVersion(ctx, logger, actionManager, workflowTemplate)
+
+		filter, err := distributeFilter.load()
+		exitIfError(logger, err)
+
+		opts := action.DistributeOptions{
+			Filter:        filter,
+			PullRequest:   *pullRequest,
+			CommitMessage: message,
+			Branch:        *branch,
+			Author:        newCommitAuthor("author", *authorName, *authorEmail),
+			Committer:     newCommitAuthor("committer", *committerName, *committerEmail),
+		}
+		if *signingKey != "" {
+			if opts.Author == nil {
+				actionCmd.Fatalf("flags --author-name and --author-email must be provided to sign commits")
+			}
+			opts.Signer, err = action.NewSigner(*signingFormat, *signingKey)
+			exitIfError(logger, err)
+			opts.RequireSigning = *requireSigning
+		} else if *requireSigning {
+			actionCmd.Fatalf("flag --signing-key must be provided with --require-signing")
+		}
+		if *withDependabot {
+			opts.DependabotSchedule = *dependabotSchedule
+		}
+		if *injectJob != "" && *injectInto == "" {
+			actionCmd.Fatalf("flag --inject-into must be provided with --inject-job")
+		}
+		opts.InjectInto = *injectInto
+		opts.InjectJob = *injectJob
+		opts.AllowDuplicates = *allowDuplicates
+		opts.VerifyRuns = *verifyRuns
+		opts.RunTimeout = *runTimeout
+		opts.MaxRunFailureRatio = *maxRunFailureRatio
+		if *maxRunFailureRatio > 0 && *waves == "" {
+			actionCmd.Fatalf("flag --waves must be provided with --max-run-failure-ratio, or use the --breaker-* flags to stop on failed workflow runs")
+		}
+		if *waves != "" {
+			opts.Waves, err = action.ParseWaves(*waves)
+			exitIfError(logger, err)
+			opts.WaveSeed = *waveSeed
+			opts.MaxFailureRatio = *maxFailureRatio
+			if !*dryRun {
+				if *waveDelay == 0 && *distributeFilter.repositoriesFrom == "-" {
+					actionCmd.Fatalf("flag --wave-delay must be provided with --waves when reading --repositories-from standard input")
+				}
+				opts.BeforeWave = waitForWave(logger, *waveDelay)
+			}
+		}
+
+		report, rolloutErr := actionManager.Distribute(ctx, opts)
+		if report == nil {
+			exitIfError(logger, rolloutErr)
+		}
+
+		for _, repository := range report.Repositories {
+			if repository.Status == action.StatusFailed {
+				level.Info(logger).Log("event", "distribute.failure", "repository", repository.Repository, "error", repository.Error)
+			}
+			for _, file := range repository.Files {
+				if file.Diff != "" {
+					_, err := io.WriteString(os.Stdout, file.Diff)
+					exitIfError(logger, err)
+				}
+			}
+		}
+
+		if *reportFile != "" {
+			err := report.Write(*reportFile)
+			exitIfError(logger, err)
+		}
+
+		var summary []interface{}
+		for _, status := range action.ReportStatuses {
+			summary = append(summary, status, report.Count(status))
+		}
+		if *verifyRuns {
+			for _, status := range action.RunStatuses {
+				summary = append(summary, "run-"+status, report.CountRuns(status))
+			}
+		}
+		level.Info(logger).Log(summary...)
+		exitIfError(logger, rolloutErr)
+
+	case coverageCmd.FullCommand():
+		filter, err := coverageFilter.load()
+		exitIfError(logger, err)
+
+		workerPool := worker.NewWorkerPool(*coverageConcurrency)
+		githubClient := newGitHubClient(ctx, nil)
+
+		actionManager := action.NewActionManager(ctx, logger, *organisation, false, nil, workerPool, newServices(githubClient))
+
+		coverage, failures, err := actionManager.Coverage(ctx, filter)
+		exitIfError(logger, err)
+
+		statuses := make(map[string]int)
+		for _, c := range coverage {
+			statuses[c.Status]++
+			if c.Status == action.CoverageNoDockerfiles && len(c.Stale) == 0 {
+				continue
+			}
+			level.Info(logger).Log("event", "coverage", "repository", c.Repository, "status", c.Status,
+				"uncovered", strings.Join(c.Uncovered, ","), "stale", strings.Join(c.Stale, ","))
+		}
+		level.Info(logger).Log(
+			action.CoverageCovered, statuses[action.CoverageCovered],
+			action.CoverageUncovered, statuses[action.CoverageUncovered],
+			action.CoverageUnconfigured, statuses[action.CoverageUnconfigured],
+			action.CoverageNoDockerfiles, statuses[action.CoverageNoDockerfiles],
+			"failures", failures,
+		)
+
+	case driftCmd.FullCommand():
+		workflowTemplate, err := driftTemplate.load()
+		exitIfError(logger, err)
+
+		filter, err := driftFilter.load()
+		exitIfError(logger, err)
+
+		workerPool := worker.NewWorkerPool(*driftConcurrency)
+		githubClient := newGitHubClient(ctx, nil)
+
+		actionManager := action.NewActionManager(ctx, logger, *organisation, false, workflowTemplate, workerPool, newServices(githubClient))
+		driftTemplate.resolveVersion(ctx, logger, actionManager, workflowTemplate)
+
+		drift, failures, err := actionManager.Drift(ctx, filter)
+		exitIfError(logger, err)
+
+		statuses := make(map[string]int)
+		for _, d := range drift {
+			statuses[d.Status]++
+			if d.Status == action.DriftInSync {
+				continue
+			}
+			level.Info(logger).Log("event", "drift", "repository", d.Repository, "status", d.Status)
+			for _, difference := range d.Differences {
+				level.Info(logger).Log("event", "drift.difference", "repository", d.Repository, "path", difference.Path,
+					"installed", difference.Installed, "canonical", difference.Canonical)
+			}
+		}
+		level.Info(logger).Log(
+			action.DriftInSync, statuses[action.DriftInSync],
+			action.DriftDrifted, statuses[action.DriftDrifted],
+			action.DriftMissing, statuses[action.DriftMissing],
+			"failures", failures,
+		)
+
+		if statuses[action.DriftDrifted] > *maxDrifted {
+			exitIfError(logger, fmt.Errorf("%d repositories have drifted, more than the %d allowed", statuses[action.DriftDrifted], *maxDrifted))
+		}
+
+	case listCmd.FullCommand():
+		filter, err := listFilter.load()
+		exitIfError(logger, err)
+
+		githubClient := newGitHubClient(ctx, nil)
+		actionManager := action.NewActionManager(ctx, logger, *organisation, false, nil, nil, newServices(githubClient))
+
+		repositories, err := actionManager.ListRepositories(ctx, filter)
+		exitIfError(logger, err)
+
+		err = action.WriteRepositories(os.Stdout, repositories, *listOutput)
+		exitIfError(logger, err)
+
+	case lintTemplateCmd.FullCommand():
+		workflowTemplate, err := lintTemplate.load()
+		exitIfError(logger, err)
+
+		// Only GitHub knows the latest release and the SHA of a tag, otherwise linting stays offline
+		if lintTemplate.needsGitHub() {
+			githubClient := newAuthenticatedClient(ctx, nil)
+			actionManager := action.NewActionManager(ctx, logger, *organisation, false, workflowTemplate, nil, newServices(githubClient))
+			lintTemplate.resolveVersion(ctx, logger, actionManager, workflowTemplate)
+		}
+
+		err = workflowTemplate.Lint()
+		exitIfError(logger, err)
+		level.Info(logger).Log("event", "lint_template.success", "path", workflowTemplate.Path)
+
+	case templateShowCmd.FullCommand():
+		_, err := os.Stdout.Write(action.DefaultTemplate)
+		exitIfError(logger, err)
 	}
+}
 
-	workerPool := worker.NewWorkerPool(*concurrency)
+// waitForWave pauses before each wave after the first, for the delay if given,
+// or until the rollout is confirmed on standard input.
+func waitForWave(logger log.Logger, delay time.Duration) action.WaveHook {
+	return func(ctx context.Context, wave, waves int, repositories []*github.Repository) error {
+		if delay > 0 {
+			level.Info(logger).Log("event", "distribute.wave_delay", "wave", wave, "waves", waves, "delay", delay)
+			select {
+			case <-time.After(delay):
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		fmt.Fprintf(os.Stderr, "Continue with wave %d of %d, distributing to %d repositories? [y/N] ", wave, waves, len(repositories))
+		answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "yes":
+			return nil
+		default:
+			return fmt.Errorf("wave %d not confirmed", wave)
+		}
+	}
+}
+
+func newServices(client *github.Client) action.Services {
+	return action.Services{
+		Repositories: client.Repositories,
+		Git:          client.Git,
+		PullRequests: client.PullRequests,
+		Teams:        client.Teams,
+		Actions:      action.NewActionsService(client),
+	}
+}
+
+// newGitHubClient returns a client for acting on the repositories of the
+// organisation, which reports the remaining rate limit to the limiter if given.
+func newGitHubClient(ctx context.Context, limiter *worker.RateLimiter) *github.Client {
+	if *organisation == "" {
+		actionCmd.Fatalf("required flag --organisation not provided")
+	}
+	return newAuthenticatedClient(ctx, limiter)
+}
+
+// newAuthenticatedClient returns a client authenticated with the token, which
+// reports the remaining rate limit to the limiter if given.
+func newAuthenticatedClient(ctx context.Context, limiter *worker.RateLimiter) *github.Client {
+	if *token == "" {
+		actionCmd.Fatalf("required flag --token not provided")
+	}
 
 	ts := oauth2.StaticTokenSource(
 		&oauth2.Token{
 			AccessToken: *token,
 		},
 	)
-	githubClient := github.NewClient(oauth2.NewClient(ctx, ts))
+	httpClient := oauth2.NewClient(ctx, ts)
+	if limiter != nil {
+		httpClient.Transport = &worker.RateLimitTransport{
+			Base:    httpClient.Transport,
+			Limiter: limiter,
+		}
+	}
+	return github.NewClient(httpClient)
+}
 
-	actionManager := action.NewActionManager(ctx, logger, *organisation, *dryRun, workflowFile, workerPool, githubClient.Repositories)
+func newCommitAuthor(flag, name, email string) *github.CommitAuthor {
+	if name == "" && email == "" {
+		return nil
+	}
+	if name == "" || email == "" {
+		actionCmd.Fatalf("flags --%[1]s-name and --%[1]s-email must be provided together", flag)
+	}
 
-	switch command {
-	case distributeCmd.FullCommand():
-		success, failures, err := actionManager.Distribute(ctx, *private)
-		if err != nil {
-			level.Error(logger).Log("error", err)
-			os.Exit(1)
-		}
-		level.Info(logger).Log("success", success, "failures", failures)
+	return &github.CommitAuthor{
+		Name:  github.String(name),
+		Email: github.String(email),
+	}
+}
+
+func exitIfError(logger log.Logger, err error) {
+	if err != nil {
+		level.Error(logger).Log("error", err)
+		os.Exit(1)
 	}
 }
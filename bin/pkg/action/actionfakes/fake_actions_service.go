@@ -0,0 +1,130 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package actionfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/go-github/v29/github"
+	"github.com/jace-ys/mobydick-action/bin/pkg/action"
+)
+
+type FakeActionsService struct {
+	ListWorkflowRunsByFileNameStub        func(context.Context, string, string, string, *action.ListWorkflowRunsOptions) (*action.WorkflowRuns, *github.Response, error)
+	listWorkflowRunsByFileNameMutex       sync.RWMutex
+	listWorkflowRunsByFileNameArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 *action.ListWorkflowRunsOptions
+	}
+	listWorkflowRunsByFileNameReturns struct {
+		result1 *action.WorkflowRuns
+		result2 *github.Response
+		result3 error
+	}
+	listWorkflowRunsByFileNameReturnsOnCall map[int]struct {
+		result1 *action.WorkflowRuns
+		result2 *github.Response
+		result3 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeActionsService) ListWorkflowRunsByFileName(arg1 context.Context, arg2 string, arg3 string, arg4 string, arg5 *action.ListWorkflowRunsOptions) (*action.WorkflowRuns, *github.Response, error) {
+	fake.listWorkflowRunsByFileNameMutex.Lock()
+	ret, specificReturn := fake.listWorkflowRunsByFileNameReturnsOnCall[len(fake.listWorkflowRunsByFileNameArgsForCall)]
+	fake.listWorkflowRunsByFileNameArgsForCall = append(fake.listWorkflowRunsByFileNameArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 *action.ListWorkflowRunsOptions
+	}{arg1, arg2, arg3, arg4, arg5})
+	fake.recordInvocation("ListWorkflowRunsByFileName", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.listWorkflowRunsByFileNameMutex.Unlock()
+	if fake.ListWorkflowRunsByFileNameStub != nil {
+		return fake.ListWorkflowRunsByFileNameStub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	fakeReturns := fake.listWorkflowRunsByFileNameReturns
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeActionsService) ListWorkflowRunsByFileNameCallCount() int {
+	fake.listWorkflowRunsByFileNameMutex.RLock()
+	defer fake.listWorkflowRunsByFileNameMutex.RUnlock()
+	return len(fake.listWorkflowRunsByFileNameArgsForCall)
+}
+
+func (fake *FakeActionsService) ListWorkflowRunsByFileNameCalls(stub func(context.Context, string, string, string, *action.ListWorkflowRunsOptions) (*action.WorkflowRuns, *github.Response, error)) {
+	fake.listWorkflowRunsByFileNameMutex.Lock()
+	defer fake.listWorkflowRunsByFileNameMutex.Unlock()
+	fake.ListWorkflowRunsByFileNameStub = stub
+}
+
+func (fake *FakeActionsService) ListWorkflowRunsByFileNameArgsForCall(i int) (context.Context, string, string, string, *action.ListWorkflowRunsOptions) {
+	fake.listWorkflowRunsByFileNameMutex.RLock()
+	defer fake.listWorkflowRunsByFileNameMutex.RUnlock()
+	argsForCall := fake.listWorkflowRunsByFileNameArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeActionsService) ListWorkflowRunsByFileNameReturns(result1 *action.WorkflowRuns, result2 *github.Response, result3 error) {
+	fake.listWorkflowRunsByFileNameMutex.Lock()
+	defer fake.listWorkflowRunsByFileNameMutex.Unlock()
+	fake.ListWorkflowRunsByFileNameStub = nil
+	fake.listWorkflowRunsByFileNameReturns = struct {
+		result1 *action.WorkflowRuns
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeActionsService) ListWorkflowRunsByFileNameReturnsOnCall(i int, result1 *action.WorkflowRuns, result2 *github.Response, result3 error) {
+	fake.listWorkflowRunsByFileNameMutex.Lock()
+	defer fake.listWorkflowRunsByFileNameMutex.Unlock()
+	fake.ListWorkflowRunsByFileNameStub = nil
+	if fake.listWorkflowRunsByFileNameReturnsOnCall == nil {
+		fake.listWorkflowRunsByFileNameReturnsOnCall = make(map[int]struct {
+			result1 *action.WorkflowRuns
+			result2 *github.Response
+			result3 error
+		})
+	}
+	fake.listWorkflowRunsByFileNameReturnsOnCall[i] = struct {
+		result1 *action.WorkflowRuns
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeActionsService) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.listWorkflowRunsByFileNameMutex.RLock()
+	defer fake.listWorkflowRunsByFileNameMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeActionsService) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ action.ActionsService = new(FakeActionsService)
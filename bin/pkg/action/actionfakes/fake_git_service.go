@@ -0,0 +1,762 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package actionfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/go-github/v29/github"
+	"github.com/jace-ys/mobydick-action/bin/pkg/action"
+)
+
+type FakeGitService struct {
+	CreateBlobStub        func(context.Context, string, string, *github.Blob) (*github.Blob, *github.Response, error)
+	createBlobMutex       sync.RWMutex
+	createBlobArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 *github.Blob
+	}
+	createBlobReturns struct {
+		result1 *github.Blob
+		result2 *github.Response
+		result3 error
+	}
+	createBlobReturnsOnCall map[int]struct {
+		result1 *github.Blob
+		result2 *github.Response
+		result3 error
+	}
+	CreateCommitStub        func(context.Context, string, string, *github.Commit) (*github.Commit, *github.Response, error)
+	createCommitMutex       sync.RWMutex
+	createCommitArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 *github.Commit
+	}
+	createCommitReturns struct {
+		result1 *github.Commit
+		result2 *github.Response
+		result3 error
+	}
+	createCommitReturnsOnCall map[int]struct {
+		result1 *github.Commit
+		result2 *github.Response
+		result3 error
+	}
+	CreateRefStub        func(context.Context, string, string, *github.Reference) (*github.Reference, *github.Response, error)
+	createRefMutex       sync.RWMutex
+	createRefArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 *github.Reference
+	}
+	createRefReturns struct {
+		result1 *github.Reference
+		result2 *github.Response
+		result3 error
+	}
+	createRefReturnsOnCall map[int]struct {
+		result1 *github.Reference
+		result2 *github.Response
+		result3 error
+	}
+	CreateTreeStub        func(context.Context, string, string, string, []github.TreeEntry) (*github.Tree, *github.Response, error)
+	createTreeMutex       sync.RWMutex
+	createTreeArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 []github.TreeEntry
+	}
+	createTreeReturns struct {
+		result1 *github.Tree
+		result2 *github.Response
+		result3 error
+	}
+	createTreeReturnsOnCall map[int]struct {
+		result1 *github.Tree
+		result2 *github.Response
+		result3 error
+	}
+	GetCommitStub        func(context.Context, string, string, string) (*github.Commit, *github.Response, error)
+	getCommitMutex       sync.RWMutex
+	getCommitArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+	}
+	getCommitReturns struct {
+		result1 *github.Commit
+		result2 *github.Response
+		result3 error
+	}
+	getCommitReturnsOnCall map[int]struct {
+		result1 *github.Commit
+		result2 *github.Response
+		result3 error
+	}
+	GetRefStub        func(context.Context, string, string, string) (*github.Reference, *github.Response, error)
+	getRefMutex       sync.RWMutex
+	getRefArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+	}
+	getRefReturns struct {
+		result1 *github.Reference
+		result2 *github.Response
+		result3 error
+	}
+	getRefReturnsOnCall map[int]struct {
+		result1 *github.Reference
+		result2 *github.Response
+		result3 error
+	}
+	GetTreeStub        func(context.Context, string, string, string, bool) (*github.Tree, *github.Response, error)
+	getTreeMutex       sync.RWMutex
+	getTreeArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 bool
+	}
+	getTreeReturns struct {
+		result1 *github.Tree
+		result2 *github.Response
+		result3 error
+	}
+	getTreeReturnsOnCall map[int]struct {
+		result1 *github.Tree
+		result2 *github.Response
+		result3 error
+	}
+	UpdateRefStub        func(context.Context, string, string, *github.Reference, bool) (*github.Reference, *github.Response, error)
+	updateRefMutex       sync.RWMutex
+	updateRefArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 *github.Reference
+		arg5 bool
+	}
+	updateRefReturns struct {
+		result1 *github.Reference
+		result2 *github.Response
+		result3 error
+	}
+	updateRefReturnsOnCall map[int]struct {
+		result1 *github.Reference
+		result2 *github.Response
+		result3 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeGitService) CreateBlob(arg1 context.Context, arg2 string, arg3 string, arg4 *github.Blob) (*github.Blob, *github.Response, error) {
+	fake.createBlobMutex.Lock()
+	ret, specificReturn := fake.createBlobReturnsOnCall[len(fake.createBlobArgsForCall)]
+	fake.createBlobArgsForCall = append(fake.createBlobArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 *github.Blob
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("CreateBlob", []interface{}{arg1, arg2, arg3, arg4})
+	fake.createBlobMutex.Unlock()
+	if fake.CreateBlobStub != nil {
+		return fake.CreateBlobStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	fakeReturns := fake.createBlobReturns
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeGitService) CreateBlobCallCount() int {
+	fake.createBlobMutex.RLock()
+	defer fake.createBlobMutex.RUnlock()
+	return len(fake.createBlobArgsForCall)
+}
+
+func (fake *FakeGitService) CreateBlobCalls(stub func(context.Context, string, string, *github.Blob) (*github.Blob, *github.Response, error)) {
+	fake.createBlobMutex.Lock()
+	defer fake.createBlobMutex.Unlock()
+	fake.CreateBlobStub = stub
+}
+
+func (fake *FakeGitService) CreateBlobArgsForCall(i int) (context.Context, string, string, *github.Blob) {
+	fake.createBlobMutex.RLock()
+	defer fake.createBlobMutex.RUnlock()
+	argsForCall := fake.createBlobArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeGitService) CreateBlobReturns(result1 *github.Blob, result2 *github.Response, result3 error) {
+	fake.createBlobMutex.Lock()
+	defer fake.createBlobMutex.Unlock()
+	fake.CreateBlobStub = nil
+	fake.createBlobReturns = struct {
+		result1 *github.Blob
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeGitService) CreateBlobReturnsOnCall(i int, result1 *github.Blob, result2 *github.Response, result3 error) {
+	fake.createBlobMutex.Lock()
+	defer fake.createBlobMutex.Unlock()
+	fake.CreateBlobStub = nil
+	if fake.createBlobReturnsOnCall == nil {
+		fake.createBlobReturnsOnCall = make(map[int]struct {
+			result1 *github.Blob
+			result2 *github.Response
+			result3 error
+		})
+	}
+	fake.createBlobReturnsOnCall[i] = struct {
+		result1 *github.Blob
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeGitService) CreateCommit(arg1 context.Context, arg2 string, arg3 string, arg4 *github.Commit) (*github.Commit, *github.Response, error) {
+	fake.createCommitMutex.Lock()
+	ret, specificReturn := fake.createCommitReturnsOnCall[len(fake.createCommitArgsForCall)]
+	fake.createCommitArgsForCall = append(fake.createCommitArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 *github.Commit
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("CreateCommit", []interface{}{arg1, arg2, arg3, arg4})
+	fake.createCommitMutex.Unlock()
+	if fake.CreateCommitStub != nil {
+		return fake.CreateCommitStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	fakeReturns := fake.createCommitReturns
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeGitService) CreateCommitCallCount() int {
+	fake.createCommitMutex.RLock()
+	defer fake.createCommitMutex.RUnlock()
+	return len(fake.createCommitArgsForCall)
+}
+
+func (fake *FakeGitService) CreateCommitCalls(stub func(context.Context, string, string, *github.Commit) (*github.Commit, *github.Response, error)) {
+	fake.createCommitMutex.Lock()
+	defer fake.createCommitMutex.Unlock()
+	fake.CreateCommitStub = stub
+}
+
+func (fake *FakeGitService) CreateCommitArgsForCall(i int) (context.Context, string, string, *github.Commit) {
+	fake.createCommitMutex.RLock()
+	defer fake.createCommitMutex.RUnlock()
+	argsForCall := fake.createCommitArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeGitService) CreateCommitReturns(result1 *github.Commit, result2 *github.Response, result3 error) {
+	fake.createCommitMutex.Lock()
+	defer fake.createCommitMutex.Unlock()
+	fake.CreateCommitStub = nil
+	fake.createCommitReturns = struct {
+		result1 *github.Commit
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeGitService) CreateCommitReturnsOnCall(i int, result1 *github.Commit, result2 *github.Response, result3 error) {
+	fake.createCommitMutex.Lock()
+	defer fake.createCommitMutex.Unlock()
+	fake.CreateCommitStub = nil
+	if fake.createCommitReturnsOnCall == nil {
+		fake.createCommitReturnsOnCall = make(map[int]struct {
+			result1 *github.Commit
+			result2 *github.Response
+			result3 error
+		})
+	}
+	fake.createCommitReturnsOnCall[i] = struct {
+		result1 *github.Commit
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeGitService) CreateRef(arg1 context.Context, arg2 string, arg3 string, arg4 *github.Reference) (*github.Reference, *github.Response, error) {
+	fake.createRefMutex.Lock()
+	ret, specificReturn := fake.createRefReturnsOnCall[len(fake.createRefArgsForCall)]
+	fake.createRefArgsForCall = append(fake.createRefArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 *github.Reference
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("CreateRef", []interface{}{arg1, arg2, arg3, arg4})
+	fake.createRefMutex.Unlock()
+	if fake.CreateRefStub != nil {
+		return fake.CreateRefStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	fakeReturns := fake.createRefReturns
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeGitService) CreateRefCallCount() int {
+	fake.createRefMutex.RLock()
+	defer fake.createRefMutex.RUnlock()
+	return len(fake.createRefArgsForCall)
+}
+
+func (fake *FakeGitService) CreateRefCalls(stub func(context.Context, string, string, *github.Reference) (*github.Reference, *github.Response, error)) {
+	fake.createRefMutex.Lock()
+	defer fake.createRefMutex.Unlock()
+	fake.CreateRefStub = stub
+}
+
+func (fake *FakeGitService) CreateRefArgsForCall(i int) (context.Context, string, string, *github.Reference) {
+	fake.createRefMutex.RLock()
+	defer fake.createRefMutex.RUnlock()
+	argsForCall := fake.createRefArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeGitService) CreateRefReturns(result1 *github.Reference, result2 *github.Response, result3 error) {
+	fake.createRefMutex.Lock()
+	defer fake.createRefMutex.Unlock()
+	fake.CreateRefStub = nil
+	fake.createRefReturns = struct {
+		result1 *github.Reference
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeGitService) CreateRefReturnsOnCall(i int, result1 *github.Reference, result2 *github.Response, result3 error) {
+	fake.createRefMutex.Lock()
+	defer fake.createRefMutex.Unlock()
+	fake.CreateRefStub = nil
+	if fake.createRefReturnsOnCall == nil {
+		fake.createRefReturnsOnCall = make(map[int]struct {
+			result1 *github.Reference
+			result2 *github.Response
+			result3 error
+		})
+	}
+	fake.createRefReturnsOnCall[i] = struct {
+		result1 *github.Reference
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeGitService) CreateTree(arg1 context.Context, arg2 string, arg3 string, arg4 string, arg5 []github.TreeEntry) (*github.Tree, *github.Response, error) {
+	var arg5Copy []github.TreeEntry
+	if arg5 != nil {
+		arg5Copy = make([]github.TreeEntry, len(arg5))
+		copy(arg5Copy, arg5)
+	}
+	fake.createTreeMutex.Lock()
+	ret, specificReturn := fake.createTreeReturnsOnCall[len(fake.createTreeArgsForCall)]
+	fake.createTreeArgsForCall = append(fake.createTreeArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 []github.TreeEntry
+	}{arg1, arg2, arg3, arg4, arg5Copy})
+	fake.recordInvocation("CreateTree", []interface{}{arg1, arg2, arg3, arg4, arg5Copy})
+	fake.createTreeMutex.Unlock()
+	if fake.CreateTreeStub != nil {
+		return fake.CreateTreeStub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	fakeReturns := fake.createTreeReturns
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeGitService) CreateTreeCallCount() int {
+	fake.createTreeMutex.RLock()
+	defer fake.createTreeMutex.RUnlock()
+	return len(fake.createTreeArgsForCall)
+}
+
+func (fake *FakeGitService) CreateTreeCalls(stub func(context.Context, string, string, string, []github.TreeEntry) (*github.Tree, *github.Response, error)) {
+	fake.createTreeMutex.Lock()
+	defer fake.createTreeMutex.Unlock()
+	fake.CreateTreeStub = stub
+}
+
+func (fake *FakeGitService) CreateTreeArgsForCall(i int) (context.Context, string, string, string, []github.TreeEntry) {
+	fake.createTreeMutex.RLock()
+	defer fake.createTreeMutex.RUnlock()
+	argsForCall := fake.createTreeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeGitService) CreateTreeReturns(result1 *github.Tree, result2 *github.Response, result3 error) {
+	fake.createTreeMutex.Lock()
+	defer fake.createTreeMutex.Unlock()
+	fake.CreateTreeStub = nil
+	fake.createTreeReturns = struct {
+		result1 *github.Tree
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeGitService) CreateTreeReturnsOnCall(i int, result1 *github.Tree, result2 *github.Response, result3 error) {
+	fake.createTreeMutex.Lock()
+	defer fake.createTreeMutex.Unlock()
+	fake.CreateTreeStub = nil
+	if fake.createTreeReturnsOnCall == nil {
+		fake.createTreeReturnsOnCall = make(map[int]struct {
+			result1 *github.Tree
+			result2 *github.Response
+			result3 error
+		})
+	}
+	fake.createTreeReturnsOnCall[i] = struct {
+		result1 *github.Tree
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeGitService) GetCommit(arg1 context.Context, arg2 string, arg3 string, arg4 string) (*github.Commit, *github.Response, error) {
+	fake.getCommitMutex.Lock()
+	ret, specificReturn := fake.getCommitReturnsOnCall[len(fake.getCommitArgsForCall)]
+	fake.getCommitArgsForCall = append(fake.getCommitArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("GetCommit", []interface{}{arg1, arg2, arg3, arg4})
+	fake.getCommitMutex.Unlock()
+	if fake.GetCommitStub != nil {
+		return fake.GetCommitStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	fakeReturns := fake.getCommitReturns
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeGitService) GetCommitCallCount() int {
+	fake.getCommitMutex.RLock()
+	defer fake.getCommitMutex.RUnlock()
+	return len(fake.getCommitArgsForCall)
+}
+
+func (fake *FakeGitService) GetCommitCalls(stub func(context.Context, string, string, string) (*github.Commit, *github.Response, error)) {
+	fake.getCommitMutex.Lock()
+	defer fake.getCommitMutex.Unlock()
+	fake.GetCommitStub = stub
+}
+
+func (fake *FakeGitService) GetCommitArgsForCall(i int) (context.Context, string, string, string) {
+	fake.getCommitMutex.RLock()
+	defer fake.getCommitMutex.RUnlock()
+	argsForCall := fake.getCommitArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeGitService) GetCommitReturns(result1 *github.Commit, result2 *github.Response, result3 error) {
+	fake.getCommitMutex.Lock()
+	defer fake.getCommitMutex.Unlock()
+	fake.GetCommitStub = nil
+	fake.getCommitReturns = struct {
+		result1 *github.Commit
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeGitService) GetCommitReturnsOnCall(i int, result1 *github.Commit, result2 *github.Response, result3 error) {
+	fake.getCommitMutex.Lock()
+	defer fake.getCommitMutex.Unlock()
+	fake.GetCommitStub = nil
+	if fake.getCommitReturnsOnCall == nil {
+		fake.getCommitReturnsOnCall = make(map[int]struct {
+			result1 *github.Commit
+			result2 *github.Response
+			result3 error
+		})
+	}
+	fake.getCommitReturnsOnCall[i] = struct {
+		result1 *github.Commit
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeGitService) GetRef(arg1 context.Context, arg2 string, arg3 string, arg4 string) (*github.Reference, *github.Response, error) {
+	fake.getRefMutex.Lock()
+	ret, specificReturn := fake.getRefReturnsOnCall[len(fake.getRefArgsForCall)]
+	fake.getRefArgsForCall = append(fake.getRefArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("GetRef", []interface{}{arg1, arg2, arg3, arg4})
+	fake.getRefMutex.Unlock()
+	if fake.GetRefStub != nil {
+		return fake.GetRefStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	fakeReturns := fake.getRefReturns
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeGitService) GetRefCallCount() int {
+	fake.getRefMutex.RLock()
+	defer fake.getRefMutex.RUnlock()
+	return len(fake.getRefArgsForCall)
+}
+
+func (fake *FakeGitService) GetRefCalls(stub func(context.Context, string, string, string) (*github.Reference, *github.Response, error)) {
+	fake.getRefMutex.Lock()
+	defer fake.getRefMutex.Unlock()
+	fake.GetRefStub = stub
+}
+
+func (fake *FakeGitService) GetRefArgsForCall(i int) (context.Context, string, string, string) {
+	fake.getRefMutex.RLock()
+	defer fake.getRefMutex.RUnlock()
+	argsForCall := fake.getRefArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeGitService) GetRefReturns(result1 *github.Reference, result2 *github.Response, result3 error) {
+	fake.getRefMutex.Lock()
+	defer fake.getRefMutex.Unlock()
+	fake.GetRefStub = nil
+	fake.getRefReturns = struct {
+		result1 *github.Reference
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeGitService) GetRefReturnsOnCall(i int, result1 *github.Reference, result2 *github.Response, result3 error) {
+	fake.getRefMutex.Lock()
+	defer fake.getRefMutex.Unlock()
+	fake.GetRefStub = nil
+	if fake.getRefReturnsOnCall == nil {
+		fake.getRefReturnsOnCall = make(map[int]struct {
+			result1 *github.Reference
+			result2 *github.Response
+			result3 error
+		})
+	}
+	fake.getRefReturnsOnCall[i] = struct {
+		result1 *github.Reference
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeGitService) GetTree(arg1 context.Context, arg2 string, arg3 string, arg4 string, arg5 bool) (*github.Tree, *github.Response, error) {
+	fake.getTreeMutex.Lock()
+	ret, specificReturn := fake.getTreeReturnsOnCall[len(fake.getTreeArgsForCall)]
+	fake.getTreeArgsForCall = append(fake.getTreeArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 bool
+	}{arg1, arg2, arg3, arg4, arg5})
+	fake.recordInvocation("GetTree", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.getTreeMutex.Unlock()
+	if fake.GetTreeStub != nil {
+		return fake.GetTreeStub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	fakeReturns := fake.getTreeReturns
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeGitService) GetTreeCallCount() int {
+	fake.getTreeMutex.RLock()
+	defer fake.getTreeMutex.RUnlock()
+	return len(fake.getTreeArgsForCall)
+}
+
+func (fake *FakeGitService) GetTreeCalls(stub func(context.Context, string, string, string, bool) (*github.Tree, *github.Response, error)) {
+	fake.getTreeMutex.Lock()
+	defer fake.getTreeMutex.Unlock()
+	fake.GetTreeStub = stub
+}
+
+func (fake *FakeGitService) GetTreeArgsForCall(i int) (context.Context, string, string, string, bool) {
+	fake.getTreeMutex.RLock()
+	defer fake.getTreeMutex.RUnlock()
+	argsForCall := fake.getTreeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeGitService) GetTreeReturns(result1 *github.Tree, result2 *github.Response, result3 error) {
+	fake.getTreeMutex.Lock()
+	defer fake.getTreeMutex.Unlock()
+	fake.GetTreeStub = nil
+	fake.getTreeReturns = struct {
+		result1 *github.Tree
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeGitService) GetTreeReturnsOnCall(i int, result1 *github.Tree, result2 *github.Response, result3 error) {
+	fake.getTreeMutex.Lock()
+	defer fake.getTreeMutex.Unlock()
+	fake.GetTreeStub = nil
+	if fake.getTreeReturnsOnCall == nil {
+		fake.getTreeReturnsOnCall = make(map[int]struct {
+			result1 *github.Tree
+			result2 *github.Response
+			result3 error
+		})
+	}
+	fake.getTreeReturnsOnCall[i] = struct {
+		result1 *github.Tree
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeGitService) UpdateRef(arg1 context.Context, arg2 string, arg3 string, arg4 *github.Reference, arg5 bool) (*github.Reference, *github.Response, error) {
+	fake.updateRefMutex.Lock()
+	ret, specificReturn := fake.updateRefReturnsOnCall[len(fake.updateRefArgsForCall)]
+	fake.updateRefArgsForCall = append(fake.updateRefArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 *github.Reference
+		arg5 bool
+	}{arg1, arg2, arg3, arg4, arg5})
+	fake.recordInvocation("UpdateRef", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.updateRefMutex.Unlock()
+	if fake.UpdateRefStub != nil {
+		return fake.UpdateRefStub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	fakeReturns := fake.updateRefReturns
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeGitService) UpdateRefCallCount() int {
+	fake.updateRefMutex.RLock()
+	defer fake.updateRefMutex.RUnlock()
+	return len(fake.updateRefArgsForCall)
+}
+
+func (fake *FakeGitService) UpdateRefCalls(stub func(context.Context, string, string, *github.Reference, bool) (*github.Reference, *github.Response, error)) {
+	fake.updateRefMutex.Lock()
+	defer fake.updateRefMutex.Unlock()
+	fake.UpdateRefStub = stub
+}
+
+func (fake *FakeGitService) UpdateRefArgsForCall(i int) (context.Context, string, string, *github.Reference, bool) {
+	fake.updateRefMutex.RLock()
+	defer fake.updateRefMutex.RUnlock()
+	argsForCall := fake.updateRefArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeGitService) UpdateRefReturns(result1 *github.Reference, result2 *github.Response, result3 error) {
+	fake.updateRefMutex.Lock()
+	defer fake.updateRefMutex.Unlock()
+	fake.UpdateRefStub = nil
+	fake.updateRefReturns = struct {
+		result1 *github.Reference
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeGitService) UpdateRefReturnsOnCall(i int, result1 *github.Reference, result2 *github.Response, result3 error) {
+	fake.updateRefMutex.Lock()
+	defer fake.updateRefMutex.Unlock()
+	fake.UpdateRefStub = nil
+	if fake.updateRefReturnsOnCall == nil {
+		fake.updateRefReturnsOnCall = make(map[int]struct {
+			result1 *github.Reference
+			result2 *github.Response
+			result3 error
+		})
+	}
+	fake.updateRefReturnsOnCall[i] = struct {
+		result1 *github.Reference
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeGitService) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.createBlobMutex.RLock()
+	defer fake.createBlobMutex.RUnlock()
+	fake.createCommitMutex.RLock()
+	defer fake.createCommitMutex.RUnlock()
+	fake.createRefMutex.RLock()
+	defer fake.createRefMutex.RUnlock()
+	fake.createTreeMutex.RLock()
+	defer fake.createTreeMutex.RUnlock()
+	fake.getCommitMutex.RLock()
+	defer fake.getCommitMutex.RUnlock()
+	fake.getRefMutex.RLock()
+	defer fake.getRefMutex.RUnlock()
+	fake.getTreeMutex.RLock()
+	defer fake.getTreeMutex.RUnlock()
+	fake.updateRefMutex.RLock()
+	defer fake.updateRefMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeGitService) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ action.GitService = new(FakeGitService)
@@ -0,0 +1,217 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package actionfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/go-github/v29/github"
+	"github.com/jace-ys/mobydick-action/bin/pkg/action"
+)
+
+type FakePullRequestsService struct {
+	CreateStub        func(context.Context, string, string, *github.NewPullRequest) (*github.PullRequest, *github.Response, error)
+	createMutex       sync.RWMutex
+	createArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 *github.NewPullRequest
+	}
+	createReturns struct {
+		result1 *github.PullRequest
+		result2 *github.Response
+		result3 error
+	}
+	createReturnsOnCall map[int]struct {
+		result1 *github.PullRequest
+		result2 *github.Response
+		result3 error
+	}
+	ListStub        func(context.Context, string, string, *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error)
+	listMutex       sync.RWMutex
+	listArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 *github.PullRequestListOptions
+	}
+	listReturns struct {
+		result1 []*github.PullRequest
+		result2 *github.Response
+		result3 error
+	}
+	listReturnsOnCall map[int]struct {
+		result1 []*github.PullRequest
+		result2 *github.Response
+		result3 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakePullRequestsService) Create(arg1 context.Context, arg2 string, arg3 string, arg4 *github.NewPullRequest) (*github.PullRequest, *github.Response, error) {
+	fake.createMutex.Lock()
+	ret, specificReturn := fake.createReturnsOnCall[len(fake.createArgsForCall)]
+	fake.createArgsForCall = append(fake.createArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 *github.NewPullRequest
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("Create", []interface{}{arg1, arg2, arg3, arg4})
+	fake.createMutex.Unlock()
+	if fake.CreateStub != nil {
+		return fake.CreateStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	fakeReturns := fake.createReturns
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakePullRequestsService) CreateCallCount() int {
+	fake.createMutex.RLock()
+	defer fake.createMutex.RUnlock()
+	return len(fake.createArgsForCall)
+}
+
+func (fake *FakePullRequestsService) CreateCalls(stub func(context.Context, string, string, *github.NewPullRequest) (*github.PullRequest, *github.Response, error)) {
+	fake.createMutex.Lock()
+	defer fake.createMutex.Unlock()
+	fake.CreateStub = stub
+}
+
+func (fake *FakePullRequestsService) CreateArgsForCall(i int) (context.Context, string, string, *github.NewPullRequest) {
+	fake.createMutex.RLock()
+	defer fake.createMutex.RUnlock()
+	argsForCall := fake.createArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakePullRequestsService) CreateReturns(result1 *github.PullRequest, result2 *github.Response, result3 error) {
+	fake.createMutex.Lock()
+	defer fake.createMutex.Unlock()
+	fake.CreateStub = nil
+	fake.createReturns = struct {
+		result1 *github.PullRequest
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakePullRequestsService) CreateReturnsOnCall(i int, result1 *github.PullRequest, result2 *github.Response, result3 error) {
+	fake.createMutex.Lock()
+	defer fake.createMutex.Unlock()
+	fake.CreateStub = nil
+	if fake.createReturnsOnCall == nil {
+		fake.createReturnsOnCall = make(map[int]struct {
+			result1 *github.PullRequest
+			result2 *github.Response
+			result3 error
+		})
+	}
+	fake.createReturnsOnCall[i] = struct {
+		result1 *github.PullRequest
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakePullRequestsService) List(arg1 context.Context, arg2 string, arg3 string, arg4 *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error) {
+	fake.listMutex.Lock()
+	ret, specificReturn := fake.listReturnsOnCall[len(fake.listArgsForCall)]
+	fake.listArgsForCall = append(fake.listArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 *github.PullRequestListOptions
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("List", []interface{}{arg1, arg2, arg3, arg4})
+	fake.listMutex.Unlock()
+	if fake.ListStub != nil {
+		return fake.ListStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	fakeReturns := fake.listReturns
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakePullRequestsService) ListCallCount() int {
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	return len(fake.listArgsForCall)
+}
+
+func (fake *FakePullRequestsService) ListCalls(stub func(context.Context, string, string, *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error)) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = stub
+}
+
+func (fake *FakePullRequestsService) ListArgsForCall(i int) (context.Context, string, string, *github.PullRequestListOptions) {
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	argsForCall := fake.listArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakePullRequestsService) ListReturns(result1 []*github.PullRequest, result2 *github.Response, result3 error) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = nil
+	fake.listReturns = struct {
+		result1 []*github.PullRequest
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakePullRequestsService) ListReturnsOnCall(i int, result1 []*github.PullRequest, result2 *github.Response, result3 error) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = nil
+	if fake.listReturnsOnCall == nil {
+		fake.listReturnsOnCall = make(map[int]struct {
+			result1 []*github.PullRequest
+			result2 *github.Response
+			result3 error
+		})
+	}
+	fake.listReturnsOnCall[i] = struct {
+		result1 []*github.PullRequest
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakePullRequestsService) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.createMutex.RLock()
+	defer fake.createMutex.RUnlock()
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakePullRequestsService) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ action.PullRequestsService = new(FakePullRequestsService)
@@ -29,6 +29,63 @@ type FakeRepositoriesService struct {
 		result2 *github.Response
 		result3 error
 	}
+	GetCommitSHA1Stub        func(context.Context, string, string, string, string) (string, *github.Response, error)
+	getCommitSHA1Mutex       sync.RWMutex
+	getCommitSHA1ArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 string
+	}
+	getCommitSHA1Returns struct {
+		result1 string
+		result2 *github.Response
+		result3 error
+	}
+	getCommitSHA1ReturnsOnCall map[int]struct {
+		result1 string
+		result2 *github.Response
+		result3 error
+	}
+	GetContentsStub        func(context.Context, string, string, string, *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error)
+	getContentsMutex       sync.RWMutex
+	getContentsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 *github.RepositoryContentGetOptions
+	}
+	getContentsReturns struct {
+		result1 *github.RepositoryContent
+		result2 []*github.RepositoryContent
+		result3 *github.Response
+		result4 error
+	}
+	getContentsReturnsOnCall map[int]struct {
+		result1 *github.RepositoryContent
+		result2 []*github.RepositoryContent
+		result3 *github.Response
+		result4 error
+	}
+	GetLatestReleaseStub        func(context.Context, string, string) (*github.RepositoryRelease, *github.Response, error)
+	getLatestReleaseMutex       sync.RWMutex
+	getLatestReleaseArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	getLatestReleaseReturns struct {
+		result1 *github.RepositoryRelease
+		result2 *github.Response
+		result3 error
+	}
+	getLatestReleaseReturnsOnCall map[int]struct {
+		result1 *github.RepositoryRelease
+		result2 *github.Response
+		result3 error
+	}
 	ListByOrgStub        func(context.Context, string, *github.RepositoryListByOrgOptions) ([]*github.Repository, *github.Response, error)
 	listByOrgMutex       sync.RWMutex
 	listByOrgArgsForCall []struct {
@@ -46,6 +103,25 @@ type FakeRepositoriesService struct {
 		result2 *github.Response
 		result3 error
 	}
+	UpdateFileStub        func(context.Context, string, string, string, *github.RepositoryContentFileOptions) (*github.RepositoryContentResponse, *github.Response, error)
+	updateFileMutex       sync.RWMutex
+	updateFileArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 *github.RepositoryContentFileOptions
+	}
+	updateFileReturns struct {
+		result1 *github.RepositoryContentResponse
+		result2 *github.Response
+		result3 error
+	}
+	updateFileReturnsOnCall map[int]struct {
+		result1 *github.RepositoryContentResponse
+		result2 *github.Response
+		result3 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -120,6 +196,217 @@ func (fake *FakeRepositoriesService) CreateFileReturnsOnCall(i int, result1 *git
 	}{result1, result2, result3}
 }
 
+func (fake *FakeRepositoriesService) GetCommitSHA1(arg1 context.Context, arg2 string, arg3 string, arg4 string, arg5 string) (string, *github.Response, error) {
+	fake.getCommitSHA1Mutex.Lock()
+	ret, specificReturn := fake.getCommitSHA1ReturnsOnCall[len(fake.getCommitSHA1ArgsForCall)]
+	fake.getCommitSHA1ArgsForCall = append(fake.getCommitSHA1ArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 string
+	}{arg1, arg2, arg3, arg4, arg5})
+	fake.recordInvocation("GetCommitSHA1", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.getCommitSHA1Mutex.Unlock()
+	if fake.GetCommitSHA1Stub != nil {
+		return fake.GetCommitSHA1Stub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	fakeReturns := fake.getCommitSHA1Returns
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeRepositoriesService) GetCommitSHA1CallCount() int {
+	fake.getCommitSHA1Mutex.RLock()
+	defer fake.getCommitSHA1Mutex.RUnlock()
+	return len(fake.getCommitSHA1ArgsForCall)
+}
+
+func (fake *FakeRepositoriesService) GetCommitSHA1Calls(stub func(context.Context, string, string, string, string) (string, *github.Response, error)) {
+	fake.getCommitSHA1Mutex.Lock()
+	defer fake.getCommitSHA1Mutex.Unlock()
+	fake.GetCommitSHA1Stub = stub
+}
+
+func (fake *FakeRepositoriesService) GetCommitSHA1ArgsForCall(i int) (context.Context, string, string, string, string) {
+	fake.getCommitSHA1Mutex.RLock()
+	defer fake.getCommitSHA1Mutex.RUnlock()
+	argsForCall := fake.getCommitSHA1ArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeRepositoriesService) GetCommitSHA1Returns(result1 string, result2 *github.Response, result3 error) {
+	fake.getCommitSHA1Mutex.Lock()
+	defer fake.getCommitSHA1Mutex.Unlock()
+	fake.GetCommitSHA1Stub = nil
+	fake.getCommitSHA1Returns = struct {
+		result1 string
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeRepositoriesService) GetCommitSHA1ReturnsOnCall(i int, result1 string, result2 *github.Response, result3 error) {
+	fake.getCommitSHA1Mutex.Lock()
+	defer fake.getCommitSHA1Mutex.Unlock()
+	fake.GetCommitSHA1Stub = nil
+	if fake.getCommitSHA1ReturnsOnCall == nil {
+		fake.getCommitSHA1ReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 *github.Response
+			result3 error
+		})
+	}
+	fake.getCommitSHA1ReturnsOnCall[i] = struct {
+		result1 string
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeRepositoriesService) GetContents(arg1 context.Context, arg2 string, arg3 string, arg4 string, arg5 *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error) {
+	fake.getContentsMutex.Lock()
+	ret, specificReturn := fake.getContentsReturnsOnCall[len(fake.getContentsArgsForCall)]
+	fake.getContentsArgsForCall = append(fake.getContentsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 *github.RepositoryContentGetOptions
+	}{arg1, arg2, arg3, arg4, arg5})
+	fake.recordInvocation("GetContents", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.getContentsMutex.Unlock()
+	if fake.GetContentsStub != nil {
+		return fake.GetContentsStub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3, ret.result4
+	}
+	fakeReturns := fake.getContentsReturns
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3, fakeReturns.result4
+}
+
+func (fake *FakeRepositoriesService) GetContentsCallCount() int {
+	fake.getContentsMutex.RLock()
+	defer fake.getContentsMutex.RUnlock()
+	return len(fake.getContentsArgsForCall)
+}
+
+func (fake *FakeRepositoriesService) GetContentsCalls(stub func(context.Context, string, string, string, *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error)) {
+	fake.getContentsMutex.Lock()
+	defer fake.getContentsMutex.Unlock()
+	fake.GetContentsStub = stub
+}
+
+func (fake *FakeRepositoriesService) GetContentsArgsForCall(i int) (context.Context, string, string, string, *github.RepositoryContentGetOptions) {
+	fake.getContentsMutex.RLock()
+	defer fake.getContentsMutex.RUnlock()
+	argsForCall := fake.getContentsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeRepositoriesService) GetContentsReturns(result1 *github.RepositoryContent, result2 []*github.RepositoryContent, result3 *github.Response, result4 error) {
+	fake.getContentsMutex.Lock()
+	defer fake.getContentsMutex.Unlock()
+	fake.GetContentsStub = nil
+	fake.getContentsReturns = struct {
+		result1 *github.RepositoryContent
+		result2 []*github.RepositoryContent
+		result3 *github.Response
+		result4 error
+	}{result1, result2, result3, result4}
+}
+
+func (fake *FakeRepositoriesService) GetContentsReturnsOnCall(i int, result1 *github.RepositoryContent, result2 []*github.RepositoryContent, result3 *github.Response, result4 error) {
+	fake.getContentsMutex.Lock()
+	defer fake.getContentsMutex.Unlock()
+	fake.GetContentsStub = nil
+	if fake.getContentsReturnsOnCall == nil {
+		fake.getContentsReturnsOnCall = make(map[int]struct {
+			result1 *github.RepositoryContent
+			result2 []*github.RepositoryContent
+			result3 *github.Response
+			result4 error
+		})
+	}
+	fake.getContentsReturnsOnCall[i] = struct {
+		result1 *github.RepositoryContent
+		result2 []*github.RepositoryContent
+		result3 *github.Response
+		result4 error
+	}{result1, result2, result3, result4}
+}
+
+func (fake *FakeRepositoriesService) GetLatestRelease(arg1 context.Context, arg2 string, arg3 string) (*github.RepositoryRelease, *github.Response, error) {
+	fake.getLatestReleaseMutex.Lock()
+	ret, specificReturn := fake.getLatestReleaseReturnsOnCall[len(fake.getLatestReleaseArgsForCall)]
+	fake.getLatestReleaseArgsForCall = append(fake.getLatestReleaseArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("GetLatestRelease", []interface{}{arg1, arg2, arg3})
+	fake.getLatestReleaseMutex.Unlock()
+	if fake.GetLatestReleaseStub != nil {
+		return fake.GetLatestReleaseStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	fakeReturns := fake.getLatestReleaseReturns
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeRepositoriesService) GetLatestReleaseCallCount() int {
+	fake.getLatestReleaseMutex.RLock()
+	defer fake.getLatestReleaseMutex.RUnlock()
+	return len(fake.getLatestReleaseArgsForCall)
+}
+
+func (fake *FakeRepositoriesService) GetLatestReleaseCalls(stub func(context.Context, string, string) (*github.RepositoryRelease, *github.Response, error)) {
+	fake.getLatestReleaseMutex.Lock()
+	defer fake.getLatestReleaseMutex.Unlock()
+	fake.GetLatestReleaseStub = stub
+}
+
+func (fake *FakeRepositoriesService) GetLatestReleaseArgsForCall(i int) (context.Context, string, string) {
+	fake.getLatestReleaseMutex.RLock()
+	defer fake.getLatestReleaseMutex.RUnlock()
+	argsForCall := fake.getLatestReleaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeRepositoriesService) GetLatestReleaseReturns(result1 *github.RepositoryRelease, result2 *github.Response, result3 error) {
+	fake.getLatestReleaseMutex.Lock()
+	defer fake.getLatestReleaseMutex.Unlock()
+	fake.GetLatestReleaseStub = nil
+	fake.getLatestReleaseReturns = struct {
+		result1 *github.RepositoryRelease
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeRepositoriesService) GetLatestReleaseReturnsOnCall(i int, result1 *github.RepositoryRelease, result2 *github.Response, result3 error) {
+	fake.getLatestReleaseMutex.Lock()
+	defer fake.getLatestReleaseMutex.Unlock()
+	fake.GetLatestReleaseStub = nil
+	if fake.getLatestReleaseReturnsOnCall == nil {
+		fake.getLatestReleaseReturnsOnCall = make(map[int]struct {
+			result1 *github.RepositoryRelease
+			result2 *github.Response
+			result3 error
+		})
+	}
+	fake.getLatestReleaseReturnsOnCall[i] = struct {
+		result1 *github.RepositoryRelease
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakeRepositoriesService) ListByOrg(arg1 context.Context, arg2 string, arg3 *github.RepositoryListByOrgOptions) ([]*github.Repository, *github.Response, error) {
 	fake.listByOrgMutex.Lock()
 	ret, specificReturn := fake.listByOrgReturnsOnCall[len(fake.listByOrgArgsForCall)]
@@ -188,13 +475,91 @@ func (fake *FakeRepositoriesService) ListByOrgReturnsOnCall(i int, result1 []*gi
 	}{result1, result2, result3}
 }
 
+func (fake *FakeRepositoriesService) UpdateFile(arg1 context.Context, arg2 string, arg3 string, arg4 string, arg5 *github.RepositoryContentFileOptions) (*github.RepositoryContentResponse, *github.Response, error) {
+	fake.updateFileMutex.Lock()
+	ret, specificReturn := fake.updateFileReturnsOnCall[len(fake.updateFileArgsForCall)]
+	fake.updateFileArgsForCall = append(fake.updateFileArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 *github.RepositoryContentFileOptions
+	}{arg1, arg2, arg3, arg4, arg5})
+	fake.recordInvocation("UpdateFile", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.updateFileMutex.Unlock()
+	if fake.UpdateFileStub != nil {
+		return fake.UpdateFileStub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	fakeReturns := fake.updateFileReturns
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeRepositoriesService) UpdateFileCallCount() int {
+	fake.updateFileMutex.RLock()
+	defer fake.updateFileMutex.RUnlock()
+	return len(fake.updateFileArgsForCall)
+}
+
+func (fake *FakeRepositoriesService) UpdateFileCalls(stub func(context.Context, string, string, string, *github.RepositoryContentFileOptions) (*github.RepositoryContentResponse, *github.Response, error)) {
+	fake.updateFileMutex.Lock()
+	defer fake.updateFileMutex.Unlock()
+	fake.UpdateFileStub = stub
+}
+
+func (fake *FakeRepositoriesService) UpdateFileArgsForCall(i int) (context.Context, string, string, string, *github.RepositoryContentFileOptions) {
+	fake.updateFileMutex.RLock()
+	defer fake.updateFileMutex.RUnlock()
+	argsForCall := fake.updateFileArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeRepositoriesService) UpdateFileReturns(result1 *github.RepositoryContentResponse, result2 *github.Response, result3 error) {
+	fake.updateFileMutex.Lock()
+	defer fake.updateFileMutex.Unlock()
+	fake.UpdateFileStub = nil
+	fake.updateFileReturns = struct {
+		result1 *github.RepositoryContentResponse
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeRepositoriesService) UpdateFileReturnsOnCall(i int, result1 *github.RepositoryContentResponse, result2 *github.Response, result3 error) {
+	fake.updateFileMutex.Lock()
+	defer fake.updateFileMutex.Unlock()
+	fake.UpdateFileStub = nil
+	if fake.updateFileReturnsOnCall == nil {
+		fake.updateFileReturnsOnCall = make(map[int]struct {
+			result1 *github.RepositoryContentResponse
+			result2 *github.Response
+			result3 error
+		})
+	}
+	fake.updateFileReturnsOnCall[i] = struct {
+		result1 *github.RepositoryContentResponse
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakeRepositoriesService) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.createFileMutex.RLock()
 	defer fake.createFileMutex.RUnlock()
+	fake.getCommitSHA1Mutex.RLock()
+	defer fake.getCommitSHA1Mutex.RUnlock()
+	fake.getContentsMutex.RLock()
+	defer fake.getContentsMutex.RUnlock()
+	fake.getLatestReleaseMutex.RLock()
+	defer fake.getLatestReleaseMutex.RUnlock()
 	fake.listByOrgMutex.RLock()
 	defer fake.listByOrgMutex.RUnlock()
+	fake.updateFileMutex.RLock()
+	defer fake.updateFileMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,128 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package actionfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/go-github/v29/github"
+	"github.com/jace-ys/mobydick-action/bin/pkg/action"
+)
+
+type FakeTeamsService struct {
+	ListTeamReposBySlugStub        func(context.Context, string, string, *github.ListOptions) ([]*github.Repository, *github.Response, error)
+	listTeamReposBySlugMutex       sync.RWMutex
+	listTeamReposBySlugArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 *github.ListOptions
+	}
+	listTeamReposBySlugReturns struct {
+		result1 []*github.Repository
+		result2 *github.Response
+		result3 error
+	}
+	listTeamReposBySlugReturnsOnCall map[int]struct {
+		result1 []*github.Repository
+		result2 *github.Response
+		result3 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeTeamsService) ListTeamReposBySlug(arg1 context.Context, arg2 string, arg3 string, arg4 *github.ListOptions) ([]*github.Repository, *github.Response, error) {
+	fake.listTeamReposBySlugMutex.Lock()
+	ret, specificReturn := fake.listTeamReposBySlugReturnsOnCall[len(fake.listTeamReposBySlugArgsForCall)]
+	fake.listTeamReposBySlugArgsForCall = append(fake.listTeamReposBySlugArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 *github.ListOptions
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("ListTeamReposBySlug", []interface{}{arg1, arg2, arg3, arg4})
+	fake.listTeamReposBySlugMutex.Unlock()
+	if fake.ListTeamReposBySlugStub != nil {
+		return fake.ListTeamReposBySlugStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	fakeReturns := fake.listTeamReposBySlugReturns
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeTeamsService) ListTeamReposBySlugCallCount() int {
+	fake.listTeamReposBySlugMutex.RLock()
+	defer fake.listTeamReposBySlugMutex.RUnlock()
+	return len(fake.listTeamReposBySlugArgsForCall)
+}
+
+func (fake *FakeTeamsService) ListTeamReposBySlugCalls(stub func(context.Context, string, string, *github.ListOptions) ([]*github.Repository, *github.Response, error)) {
+	fake.listTeamReposBySlugMutex.Lock()
+	defer fake.listTeamReposBySlugMutex.Unlock()
+	fake.ListTeamReposBySlugStub = stub
+}
+
+func (fake *FakeTeamsService) ListTeamReposBySlugArgsForCall(i int) (context.Context, string, string, *github.ListOptions) {
+	fake.listTeamReposBySlugMutex.RLock()
+	defer fake.listTeamReposBySlugMutex.RUnlock()
+	argsForCall := fake.listTeamReposBySlugArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeTeamsService) ListTeamReposBySlugReturns(result1 []*github.Repository, result2 *github.Response, result3 error) {
+	fake.listTeamReposBySlugMutex.Lock()
+	defer fake.listTeamReposBySlugMutex.Unlock()
+	fake.ListTeamReposBySlugStub = nil
+	fake.listTeamReposBySlugReturns = struct {
+		result1 []*github.Repository
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeTeamsService) ListTeamReposBySlugReturnsOnCall(i int, result1 []*github.Repository, result2 *github.Response, result3 error) {
+	fake.listTeamReposBySlugMutex.Lock()
+	defer fake.listTeamReposBySlugMutex.Unlock()
+	fake.ListTeamReposBySlugStub = nil
+	if fake.listTeamReposBySlugReturnsOnCall == nil {
+		fake.listTeamReposBySlugReturnsOnCall = make(map[int]struct {
+			result1 []*github.Repository
+			result2 *github.Response
+			result3 error
+		})
+	}
+	fake.listTeamReposBySlugReturnsOnCall[i] = struct {
+		result1 []*github.Repository
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeTeamsService) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.listTeamReposBySlugMutex.RLock()
+	defer fake.listTeamReposBySlugMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeTeamsService) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ action.TeamsService = new(FakeTeamsService)
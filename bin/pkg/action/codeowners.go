@@ -0,0 +1,147 @@
+package action
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-github/v29/github"
+)
+
+// CodeownersPaths are the locations of the CODEOWNERS file that are checked,
+// in the order GitHub looks them up.
+var CodeownersPaths = []string{".github/CODEOWNERS", "CODEOWNERS"}
+
+type Codeowners struct {
+	Rules []*CodeownersRule
+}
+
+type CodeownersRule struct {
+	Pattern string
+	Owners  []string
+	regexp  *regexp.Regexp
+}
+
+func ParseCodeowners(content []byte) *Codeowners {
+	codeowners := &Codeowners{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		codeowners.Rules = append(codeowners.Rules, &CodeownersRule{
+			Pattern: fields[0],
+			Owners:  fields[1:],
+			regexp:  codeownersRegexp(fields[0]),
+		})
+	}
+
+	return codeowners
+}
+
+// Owners returns the owners of the file at path, which are given by the last
+// rule that matches it, as in GitHub.
+func (c *Codeowners) Owners(path string) []string {
+	for i := len(c.Rules) - 1; i >= 0; i-- {
+		if c.Rules[i].regexp.MatchString(path) {
+			return c.Rules[i].Owners
+		}
+	}
+	return nil
+}
+
+func (c *Codeowners) Owns(owner, path string) bool {
+	for _, o := range c.Owners(path) {
+		if strings.EqualFold(o, owner) {
+			return true
+		}
+	}
+	return false
+}
+
+// codeownersRegexp translates a CODEOWNERS pattern, which follows the rules of
+// .gitignore files, into a regular expression matching the paths it covers.
+func codeownersRegexp(pattern string) *regexp.Regexp {
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+
+	// A pattern naming a directory covers everything within it, but unlike in
+	// .gitignore files, a wildcard such as docs/* only matches direct children
+	segments := strings.Split(strings.TrimSuffix(pattern, "/"), "/")
+	descendants := strings.HasSuffix(pattern, "/") || !strings.Contains(segments[len(segments)-1], "*")
+
+	pattern = strings.Trim(pattern, "/")
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	if !anchored {
+		expr.WriteString("(.*/)?")
+	}
+
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expr.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expr.WriteString("[^/]*")
+		case pattern[i] == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+
+	if descendants {
+		expr.WriteString("(/.*)?")
+	}
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String())
+}
+
+// GetCodeowners returns the CODEOWNERS file on the default branch of the
+// repository, or nil if it has none.
+func (am *ActionManager) GetCodeowners(ctx context.Context, repository *github.Repository) (*Codeowners, error) {
+	for _, path := range CodeownersPaths {
+		file, err := am.GetFile(ctx, repository.GetName(), path, "")
+		if err != nil {
+			return nil, err
+		}
+		if file != nil {
+			return ParseCodeowners(file.Content), nil
+		}
+	}
+	return nil, nil
+}
+
+// OwnsDockerfiles reports whether the CODEOWNERS file of the repository
+// assigns ownership of any of its Dockerfiles to owner.
+func (am *ActionManager) OwnsDockerfiles(ctx context.Context, repository *github.Repository, owner string) (bool, error) {
+	codeowners, err := am.GetCodeowners(ctx, repository)
+	if err != nil || codeowners == nil {
+		return false, err
+	}
+
+	dockerfiles, err := am.ListDockerfiles(ctx, repository)
+	if err != nil {
+		return false, err
+	}
+
+	for _, dockerfile := range dockerfiles {
+		if codeowners.Owns(owner, dockerfile) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
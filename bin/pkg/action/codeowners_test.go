@@ -0,0 +1,113 @@
+package action_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/go-github/v29/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jace-ys/mobydick-action/bin/pkg/action"
+	"github.com/jace-ys/mobydick-action/bin/pkg/action/actionfakes"
+)
+
+func TestCodeowners(t *testing.T) {
+	codeowners := action.ParseCodeowners([]byte(`# Default owners
+*                   @org/platform
+
+*.go                @org/backend
+/Dockerfile         @org/infra @alice
+docker/             @org/infra
+apps/**/Dockerfile* @org/apps # Per-app images
+/web/Docker?ile     @org/web
+images/*            @org/images
+tools/*/            @org/tools
+`))
+
+	testCases := []struct {
+		path   string
+		owners []string
+	}{
+		{path: "README.md", owners: []string{"@org/platform"}},
+		{path: "cmd/main.go", owners: []string{"@org/backend"}},
+		{path: "Dockerfile", owners: []string{"@org/infra", "@alice"}},
+		{path: "build/Dockerfile", owners: []string{"@org/platform"}},
+		{path: "docker/base/Dockerfile", owners: []string{"@org/infra"}},
+		{path: "services/docker/Dockerfile", owners: []string{"@org/infra"}},
+		{path: "apps/api/Dockerfile.dev", owners: []string{"@org/apps"}},
+		{path: "apps/Dockerfile", owners: []string{"@org/apps"}},
+		{path: "web/Dockerfile", owners: []string{"@org/web"}},
+		{path: "images/Dockerfile", owners: []string{"@org/images"}},
+		{path: "images/base/Dockerfile", owners: []string{"@org/platform"}},
+		{path: "tools/lint/Dockerfile", owners: []string{"@org/tools"}},
+		{path: "tools/lint/build/Dockerfile", owners: []string{"@org/tools"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			assert.Equal(t, tc.owners, codeowners.Owners(tc.path))
+		})
+	}
+
+	t.Run("Owns", func(t *testing.T) {
+		assert.True(t, codeowners.Owns("@Org/Infra", "Dockerfile"))
+		assert.False(t, codeowners.Owns("@org/infra", "README.md"))
+	})
+}
+
+func TestOwnsDockerfiles(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logger := log.NewNopLogger()
+	pullRequestsService := new(actionfakes.FakePullRequestsService)
+	repository := fakeRepositories(1)[0]
+
+	gitService := new(actionfakes.FakeGitService)
+	gitService.GetTreeReturns(fakeTree("Dockerfile", "web/Dockerfile"), &github.Response{}, nil)
+
+	t.Run("GitHubDirectory", func(t *testing.T) {
+		repositoriesService := new(actionfakes.FakeRepositoriesService)
+		repositoriesService.GetContentsReturns(fakeContent("web/ @org/web\n"), nil, &github.Response{}, nil)
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, nil, nil, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+		owned, err := actionManager.OwnsDockerfiles(ctx, repository, "@org/web")
+
+		require.NoError(t, err)
+		assert.True(t, owned)
+
+		_, _, _, path, _ := repositoriesService.GetContentsArgsForCall(0)
+		assert.Equal(t, ".github/CODEOWNERS", path)
+	})
+
+	t.Run("Root", func(t *testing.T) {
+		repositoriesService := new(actionfakes.FakeRepositoriesService)
+		repositoriesService.GetContentsReturnsOnCall(0, nil, nil, fakeResponse(http.StatusNotFound), fmt.Errorf("not found"))
+		repositoriesService.GetContentsReturnsOnCall(1, fakeContent("*.md @org/docs\n"), nil, &github.Response{}, nil)
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, nil, nil, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+		owned, err := actionManager.OwnsDockerfiles(ctx, repository, "@org/web")
+
+		require.NoError(t, err)
+		assert.False(t, owned)
+
+		_, _, _, path, _ := repositoriesService.GetContentsArgsForCall(1)
+		assert.Equal(t, "CODEOWNERS", path)
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		repositoriesService := new(actionfakes.FakeRepositoriesService)
+		repositoriesService.GetContentsReturns(nil, nil, fakeResponse(http.StatusNotFound), fmt.Errorf("not found"))
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, nil, nil, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+		owned, err := actionManager.OwnsDockerfiles(ctx, repository, "@org/web")
+
+		require.NoError(t, err)
+		assert.False(t, owned)
+		assert.Equal(t, 2, repositoriesService.GetContentsCallCount())
+	})
+}
@@ -0,0 +1,278 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/google/go-github/v29/github"
+)
+
+const (
+	DefaultCommitMessage     = "GitHub Actions workflow for Mobydick"
+	DefaultPullRequestBranch = "mobydick"
+	maxCommitAttempts        = 3
+)
+
+var (
+	errNonFastForward = errors.New("branch has moved on since the commit was created")
+	errNoChanges      = errors.New("branch already contains the files")
+)
+
+// CommitOptions describe the commits made to a repository. An empty branch
+// targets the default branch, or DefaultPullRequestBranch in pull request mode,
+// and a nil author or committer is left to GitHub to fill in from the token.
+// Commits are made unsigned if signing fails, unless signing is required.
+type CommitOptions struct {
+	Message        string
+	Branch         string
+	Author         *github.CommitAuthor
+	Committer      *github.CommitAuthor
+	PullRequest    bool
+	Signer         Signer
+	RequireSigning bool
+}
+
+func (c CommitOptions) fileOptions(content []byte) *github.RepositoryContentFileOptions {
+	opts := &github.RepositoryContentFileOptions{
+		Message:   github.String(c.Message),
+		Content:   content,
+		Author:    c.Author,
+		Committer: c.Committer,
+	}
+	if c.Branch != "" {
+		opts.Branch = github.String(c.Branch)
+	}
+	return opts
+}
+
+type CommitMessage struct {
+	template *template.Template
+}
+
+type CommitMessageVariables struct {
+	Organisation string
+	Repository   string
+	Version      string
+}
+
+func NewCommitMessage(text string) (*CommitMessage, error) {
+	tmpl, err := template.New("commit-message").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse commit message: %w", err)
+	}
+	return &CommitMessage{template: tmpl}, nil
+}
+
+func (cm *CommitMessage) Render(variables CommitMessageVariables) (string, error) {
+	var buf bytes.Buffer
+	err := cm.template.Execute(&buf, variables)
+	if err != nil {
+		return "", fmt.Errorf("failed to render commit message: %w", err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+type CommitRequest struct {
+	Repository     string
+	BaseBranch     string
+	Branch         string
+	Message        string
+	Author         *github.CommitAuthor
+	Committer      *github.CommitAuthor
+	Files          []*RepositoryFile
+	PullRequest    bool
+	Signer         Signer
+	RequireSigning bool
+}
+
+// CommitResult describes the commit made, which is skipped when the branch
+// already contains the files, as it can in pull request mode.
+type CommitResult struct {
+	SHA         string
+	PullRequest string
+	Unchanged   bool
+}
+
+// Commit creates a single commit containing all the files in the request using
+// the Git Data API, and fast-forwards the branch to it. If the branch moves on
+// while the commit is being created, the commit is recreated on top of it. In
+// pull request mode, the branch is created from the base branch if needed and a
+// pull request is opened unless one exists already.
+func (am *ActionManager) Commit(ctx context.Context, request *CommitRequest) (*CommitResult, error) {
+	if am.dryRun {
+		level.Info(am.logger).Log("event", "commit.dry_run", "repository", request.Repository, "branch", request.Branch, "files", len(request.Files))
+		return &CommitResult{}, nil
+	}
+
+	var sha string
+	var err error
+	for attempt := 1; attempt <= maxCommitAttempts; attempt++ {
+		sha, err = am.commit(ctx, request)
+		if !errors.Is(err, errNonFastForward) {
+			break
+		}
+		level.Info(am.logger).Log("event", "commit.retry", "repository", request.Repository, "branch", request.Branch, "attempt", attempt)
+	}
+	if errors.Is(err, errNoChanges) {
+		level.Info(am.logger).Log("event", "commit.unchanged", "repository", request.Repository, "branch", request.Branch)
+		return &CommitResult{Unchanged: true}, nil
+	}
+	if err != nil {
+		level.Info(am.logger).Log("event", "commit.failure", "repository", request.Repository, "branch", request.Branch, "error", err)
+		return nil, err
+	}
+
+	result := &CommitResult{SHA: sha}
+	level.Info(am.logger).Log("event", "commit.success", "repository", request.Repository, "branch", request.Branch, "sha", sha)
+
+	if request.PullRequest {
+		pull, err := am.openPullRequest(ctx, request)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open pull request: %w", err)
+		}
+		result.PullRequest = pull.GetHTMLURL()
+		level.Info(am.logger).Log("event", "pull_request.success", "repository", request.Repository, "url", result.PullRequest)
+	}
+
+	return result, nil
+}
+
+func (am *ActionManager) commit(ctx context.Context, request *CommitRequest) (string, error) {
+	ref, err := am.branchRef(ctx, request)
+	if err != nil {
+		return "", fmt.Errorf("failed to get branch: %w", err)
+	}
+
+	parent, _, err := am.gitService.GetCommit(ctx, am.organisation, request.Repository, ref.GetObject().GetSHA())
+	if err != nil {
+		return "", fmt.Errorf("failed to get commit: %w", err)
+	}
+
+	var entries []github.TreeEntry
+	for _, file := range request.Files {
+		blob, _, err := am.gitService.CreateBlob(ctx, am.organisation, request.Repository, &github.Blob{
+			Content:  github.String(string(file.Content)),
+			Encoding: github.String("utf-8"),
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to create blob: %w", err)
+		}
+
+		entries = append(entries, github.TreeEntry{
+			Path: github.String(file.Path),
+			Mode: github.String("100644"),
+			Type: github.String("blob"),
+			SHA:  github.String(blob.GetSHA()),
+		})
+	}
+
+	tree, _, err := am.gitService.CreateTree(ctx, am.organisation, request.Repository, parent.GetTree().GetSHA(), entries)
+	if err != nil {
+		return "", fmt.Errorf("failed to create tree: %w", err)
+	}
+
+	// A pull request branch left over from an earlier run may contain the files already
+	if tree.GetSHA() == parent.GetTree().GetSHA() {
+		return "", errNoChanges
+	}
+
+	commit := &github.Commit{
+		Message:   github.String(request.Message),
+		Author:    request.Author,
+		Committer: request.Committer,
+		Tree:      tree,
+		Parents:   []github.Commit{{SHA: github.String(parent.GetSHA())}},
+	}
+	if request.Signer != nil {
+		err := signCommit(request.Signer, commit)
+		if err != nil {
+			if request.RequireSigning {
+				return "", err
+			}
+			level.Info(am.logger).Log("event", "commit.unsigned", "repository", request.Repository, "error", err)
+		}
+	}
+
+	commit, _, err = am.gitService.CreateCommit(ctx, am.organisation, request.Repository, commit)
+	if err != nil {
+		return "", fmt.Errorf("failed to create commit: %w", err)
+	}
+
+	// Leave the branch alone rather than pointing it at a commit that branch protection will reject
+	if request.RequireSigning && !commit.GetVerification().GetVerified() {
+		return "", fmt.Errorf("commit signature could not be verified: %s", commit.GetVerification().GetReason())
+	}
+
+	update := &github.Reference{
+		Ref:    github.String(ref.GetRef()),
+		Object: &github.GitObject{SHA: github.String(commit.GetSHA())},
+	}
+	_, response, err := am.gitService.UpdateRef(ctx, am.organisation, request.Repository, update, false)
+	if err != nil {
+		if response != nil && response.Response != nil && response.StatusCode == http.StatusUnprocessableEntity {
+			return "", fmt.Errorf("%w: %s", errNonFastForward, err)
+		}
+		return "", fmt.Errorf("failed to update branch: %w", err)
+	}
+
+	return commit.GetSHA(), nil
+}
+
+// branchRef returns the ref of the branch to commit to, creating it from the base
+// branch in pull request mode if it doesn't exist yet.
+func (am *ActionManager) branchRef(ctx context.Context, request *CommitRequest) (*github.Reference, error) {
+	ref, response, err := am.gitService.GetRef(ctx, am.organisation, request.Repository, "heads/"+request.Branch)
+	if err == nil {
+		return ref, nil
+	}
+	if !request.PullRequest || request.Branch == request.BaseBranch || response == nil || response.Response == nil || response.StatusCode != http.StatusNotFound {
+		return nil, err
+	}
+
+	base, _, err := am.gitService.GetRef(ctx, am.organisation, request.Repository, "heads/"+request.BaseBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	ref, _, err = am.gitService.CreateRef(ctx, am.organisation, request.Repository, &github.Reference{
+		Ref:    github.String("refs/heads/" + request.Branch),
+		Object: &github.GitObject{SHA: github.String(base.GetObject().GetSHA())},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ref, nil
+}
+
+func (am *ActionManager) openPullRequest(ctx context.Context, request *CommitRequest) (*github.PullRequest, error) {
+	pulls, _, err := am.pullRequestsService.List(ctx, am.organisation, request.Repository, &github.PullRequestListOptions{
+		State: "open",
+		Head:  am.organisation + ":" + request.Branch,
+		Base:  request.BaseBranch,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(pulls) > 0 {
+		return pulls[0], nil
+	}
+
+	title := strings.SplitN(request.Message, "\n", 2)[0]
+	pull, _, err := am.pullRequestsService.Create(ctx, am.organisation, request.Repository, &github.NewPullRequest{
+		Title: github.String(title),
+		Head:  github.String(request.Branch),
+		Base:  github.String(request.BaseBranch),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pull, nil
+}
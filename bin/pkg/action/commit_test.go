@@ -0,0 +1,337 @@
+package action_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/go-github/v29/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jace-ys/mobydick-action/bin/pkg/action"
+	"github.com/jace-ys/mobydick-action/bin/pkg/action/actionfakes"
+	"github.com/jace-ys/mobydick-action/bin/pkg/worker"
+)
+
+func TestCommit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logger := log.NewNopLogger()
+	workerPool := worker.NewWorkerPool(1)
+	repositoriesService := new(actionfakes.FakeRepositoriesService)
+
+	files := []*action.RepositoryFile{
+		{Path: ".github/workflows/mobydick.yaml", Content: []byte("workflow")},
+		{Path: ".github/dependabot.yml", Content: []byte("dependabot")},
+	}
+
+	t.Run("Direct", func(t *testing.T) {
+		gitService := fakeGitService()
+		pullRequestsService := new(actionfakes.FakePullRequestsService)
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, nil, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+		result, err := actionManager.Commit(ctx, &action.CommitRequest{
+			Repository: "repository",
+			BaseBranch: "main",
+			Branch:     "main",
+			Message:    "message",
+			Files:      files,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "commit", result.SHA)
+		assert.Equal(t, 2, gitService.CreateBlobCallCount())
+
+		_, _, _, baseTree, entries := gitService.CreateTreeArgsForCall(0)
+		assert.Equal(t, "tree", baseTree)
+		assert.Equal(t, ".github/workflows/mobydick.yaml", entries[0].GetPath())
+		assert.Equal(t, ".github/dependabot.yml", entries[1].GetPath())
+
+		_, _, _, commit := gitService.CreateCommitArgsForCall(0)
+		assert.Equal(t, "message", commit.GetMessage())
+		assert.Equal(t, "parent", commit.Parents[0].GetSHA())
+
+		_, _, _, ref, force := gitService.UpdateRefArgsForCall(0)
+		assert.Equal(t, "refs/heads/main", ref.GetRef())
+		assert.Equal(t, "commit", ref.GetObject().GetSHA())
+		assert.False(t, force)
+		assert.Equal(t, 0, pullRequestsService.CreateCallCount())
+	})
+
+	t.Run("RetryNonFastForward", func(t *testing.T) {
+		gitService := fakeGitService()
+		gitService.UpdateRefReturnsOnCall(0, nil, fakeResponse(http.StatusUnprocessableEntity), fmt.Errorf("update is not a fast forward"))
+		gitService.UpdateRefReturnsOnCall(1, &github.Reference{}, &github.Response{}, nil)
+		pullRequestsService := new(actionfakes.FakePullRequestsService)
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, nil, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+		_, err := actionManager.Commit(ctx, &action.CommitRequest{
+			Repository: "repository",
+			BaseBranch: "main",
+			Branch:     "main",
+			Message:    "message",
+			Files:      files,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, gitService.GetRefCallCount())
+		assert.Equal(t, 2, gitService.CreateCommitCallCount())
+		assert.Equal(t, 2, gitService.UpdateRefCallCount())
+	})
+
+	t.Run("RetryExhausted", func(t *testing.T) {
+		gitService := fakeGitService()
+		gitService.UpdateRefReturns(nil, fakeResponse(http.StatusUnprocessableEntity), fmt.Errorf("update is not a fast forward"))
+		pullRequestsService := new(actionfakes.FakePullRequestsService)
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, nil, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+		_, err := actionManager.Commit(ctx, &action.CommitRequest{
+			Repository: "repository",
+			BaseBranch: "main",
+			Branch:     "main",
+			Message:    "message",
+			Files:      files,
+		})
+
+		assert.Error(t, err)
+		assert.Equal(t, 3, gitService.UpdateRefCallCount())
+	})
+
+	t.Run("Signed", func(t *testing.T) {
+		gitService := fakeGitService()
+		gitService.CreateCommitReturns(&github.Commit{
+			SHA:          github.String("commit"),
+			Verification: &github.SignatureVerification{Verified: github.Bool(true)},
+		}, &github.Response{}, nil)
+		pullRequestsService := new(actionfakes.FakePullRequestsService)
+		signer := new(actionfakes.FakeSigner)
+		signer.SignReturns([]byte("signature"), nil)
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, nil, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+		_, err := actionManager.Commit(ctx, &action.CommitRequest{
+			Repository:     "repository",
+			BaseBranch:     "main",
+			Branch:         "main",
+			Message:        "message",
+			Author:         &github.CommitAuthor{Name: github.String("bot"), Email: github.String("bot@example.com")},
+			Files:          files,
+			Signer:         signer,
+			RequireSigning: true,
+		})
+
+		require.NoError(t, err)
+		payload := string(signer.SignArgsForCall(0))
+		assert.Contains(t, payload, "tree new-tree\nparent parent\nauthor bot <bot@example.com> ")
+		assert.Contains(t, payload, "\ncommitter bot <bot@example.com> ")
+		assert.True(t, strings.HasSuffix(payload, "\n\nmessage"))
+
+		_, _, _, commit := gitService.CreateCommitArgsForCall(0)
+		assert.Equal(t, "signature", commit.GetVerification().GetSignature())
+		assert.NotNil(t, commit.GetAuthor().Date)
+		assert.Equal(t, 1, gitService.UpdateRefCallCount())
+	})
+
+	t.Run("SigningFallback", func(t *testing.T) {
+		gitService := fakeGitService()
+		pullRequestsService := new(actionfakes.FakePullRequestsService)
+		signer := new(actionfakes.FakeSigner)
+		signer.SignReturns(nil, fmt.Errorf("no secret key"))
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, nil, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+		_, err := actionManager.Commit(ctx, &action.CommitRequest{
+			Repository: "repository",
+			BaseBranch: "main",
+			Branch:     "main",
+			Message:    "message",
+			Author:     &github.CommitAuthor{Name: github.String("bot"), Email: github.String("bot@example.com")},
+			Files:      files,
+			Signer:     signer,
+		})
+
+		require.NoError(t, err)
+		_, _, _, commit := gitService.CreateCommitArgsForCall(0)
+		assert.Nil(t, commit.Verification)
+		assert.Equal(t, 1, gitService.UpdateRefCallCount())
+	})
+
+	t.Run("SigningRequired", func(t *testing.T) {
+		pullRequestsService := new(actionfakes.FakePullRequestsService)
+
+		t.Run("SignFailure", func(t *testing.T) {
+			gitService := fakeGitService()
+			signer := new(actionfakes.FakeSigner)
+			signer.SignReturns(nil, fmt.Errorf("no secret key"))
+
+			actionManager := action.NewActionManager(ctx, logger, "organisation", false, nil, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+			_, err := actionManager.Commit(ctx, &action.CommitRequest{
+				Repository:     "repository",
+				BaseBranch:     "main",
+				Branch:         "main",
+				Message:        "message",
+				Author:         &github.CommitAuthor{Name: github.String("bot"), Email: github.String("bot@example.com")},
+				Files:          files,
+				Signer:         signer,
+				RequireSigning: true,
+			})
+
+			assert.Error(t, err)
+			assert.Equal(t, 0, gitService.CreateCommitCallCount())
+			assert.Equal(t, 0, gitService.UpdateRefCallCount())
+		})
+
+		t.Run("Unverified", func(t *testing.T) {
+			gitService := fakeGitService()
+			gitService.CreateCommitReturns(&github.Commit{
+				SHA:          github.String("commit"),
+				Verification: &github.SignatureVerification{Verified: github.Bool(false), Reason: github.String("unknown_key")},
+			}, &github.Response{}, nil)
+			signer := new(actionfakes.FakeSigner)
+			signer.SignReturns([]byte("signature"), nil)
+
+			actionManager := action.NewActionManager(ctx, logger, "organisation", false, nil, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+			_, err := actionManager.Commit(ctx, &action.CommitRequest{
+				Repository:     "repository",
+				BaseBranch:     "main",
+				Branch:         "main",
+				Message:        "message",
+				Author:         &github.CommitAuthor{Name: github.String("bot"), Email: github.String("bot@example.com")},
+				Files:          files,
+				Signer:         signer,
+				RequireSigning: true,
+			})
+
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "unknown_key")
+			assert.Equal(t, 0, gitService.UpdateRefCallCount())
+		})
+	})
+
+	t.Run("PullRequest", func(t *testing.T) {
+		gitService := fakeGitService()
+		gitService.GetRefReturnsOnCall(0, nil, fakeResponse(http.StatusNotFound), fmt.Errorf("not found"))
+		gitService.CreateRefReturns(&github.Reference{
+			Ref:    github.String("refs/heads/mobydick"),
+			Object: &github.GitObject{SHA: github.String("parent")},
+		}, &github.Response{}, nil)
+		pullRequestsService := new(actionfakes.FakePullRequestsService)
+		pullRequestsService.CreateReturns(&github.PullRequest{HTMLURL: github.String("https://github.com/organisation/repository/pull/1")}, &github.Response{}, nil)
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, nil, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+		result, err := actionManager.Commit(ctx, &action.CommitRequest{
+			Repository:  "repository",
+			BaseBranch:  "main",
+			Branch:      action.DefaultPullRequestBranch,
+			Message:     "message\n\nbody",
+			Author:      &github.CommitAuthor{Name: github.String("bot"), Email: github.String("bot@example.com")},
+			Files:       files,
+			PullRequest: true,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "https://github.com/organisation/repository/pull/1", result.PullRequest)
+
+		_, _, _, commit := gitService.CreateCommitArgsForCall(0)
+		assert.Equal(t, "bot", commit.GetAuthor().GetName())
+
+		_, _, _, ref := gitService.CreateRefArgsForCall(0)
+		assert.Equal(t, "refs/heads/mobydick", ref.GetRef())
+
+		_, _, _, updated, _ := gitService.UpdateRefArgsForCall(0)
+		assert.Equal(t, "refs/heads/mobydick", updated.GetRef())
+
+		_, _, _, pull := pullRequestsService.CreateArgsForCall(0)
+		assert.Equal(t, "message", pull.GetTitle())
+		assert.Equal(t, "mobydick", pull.GetHead())
+		assert.Equal(t, "main", pull.GetBase())
+	})
+
+	t.Run("PullRequestExisting", func(t *testing.T) {
+		gitService := fakeGitService()
+		pullRequestsService := new(actionfakes.FakePullRequestsService)
+		pullRequestsService.ListReturns([]*github.PullRequest{{HTMLURL: github.String("https://github.com/organisation/repository/pull/1")}}, &github.Response{}, nil)
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, nil, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+		result, err := actionManager.Commit(ctx, &action.CommitRequest{
+			Repository:  "repository",
+			BaseBranch:  "main",
+			Branch:      action.DefaultPullRequestBranch,
+			Message:     "message",
+			Files:       files,
+			PullRequest: true,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, gitService.CreateRefCallCount())
+		assert.Equal(t, 0, pullRequestsService.CreateCallCount())
+		assert.Equal(t, "https://github.com/organisation/repository/pull/1", result.PullRequest)
+	})
+
+	t.Run("PullRequestUnchanged", func(t *testing.T) {
+		gitService := fakeGitService()
+		gitService.CreateTreeReturns(&github.Tree{SHA: github.String("tree")}, &github.Response{}, nil)
+		pullRequestsService := new(actionfakes.FakePullRequestsService)
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, nil, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+		result, err := actionManager.Commit(ctx, &action.CommitRequest{
+			Repository:  "repository",
+			BaseBranch:  "main",
+			Branch:      action.DefaultPullRequestBranch,
+			Message:     "message",
+			Files:       files,
+			PullRequest: true,
+		})
+
+		require.NoError(t, err)
+		assert.True(t, result.Unchanged)
+		assert.Empty(t, result.SHA)
+		assert.Equal(t, 0, gitService.CreateCommitCallCount())
+		assert.Equal(t, 0, gitService.UpdateRefCallCount())
+		assert.Equal(t, 0, pullRequestsService.CreateCallCount())
+	})
+}
+
+func TestCommitMessage(t *testing.T) {
+	t.Run("Render", func(t *testing.T) {
+		message, err := action.NewCommitMessage("chore({{ .Repository }}): add mobydick {{ .Version }}\n\nDistributed to {{ .Organisation }}.\n")
+		require.NoError(t, err)
+
+		rendered, err := message.Render(action.CommitMessageVariables{Organisation: "organisation", Repository: "repository", Version: "v1.0.0"})
+		require.NoError(t, err)
+		assert.Equal(t, "chore(repository): add mobydick v1.0.0\n\nDistributed to organisation.", rendered)
+	})
+
+	t.Run("InvalidTemplate", func(t *testing.T) {
+		_, err := action.NewCommitMessage("{{ .Repository ")
+		assert.Error(t, err)
+	})
+
+	t.Run("UnknownVariable", func(t *testing.T) {
+		message, err := action.NewCommitMessage("{{ .Branch }}")
+		require.NoError(t, err)
+
+		_, err = message.Render(action.CommitMessageVariables{})
+		assert.Error(t, err)
+	})
+}
+
+func fakeGitService() *actionfakes.FakeGitService {
+	gitService := new(actionfakes.FakeGitService)
+	gitService.GetRefReturns(&github.Reference{
+		Ref:    github.String("refs/heads/main"),
+		Object: &github.GitObject{SHA: github.String("parent")},
+	}, &github.Response{}, nil)
+	gitService.GetCommitReturns(&github.Commit{
+		SHA:  github.String("parent"),
+		Tree: &github.Tree{SHA: github.String("tree")},
+	}, &github.Response{}, nil)
+	gitService.CreateBlobReturns(&github.Blob{SHA: github.String("blob")}, &github.Response{}, nil)
+	gitService.CreateTreeReturns(&github.Tree{SHA: github.String("new-tree")}, &github.Response{}, nil)
+	gitService.CreateCommitReturns(&github.Commit{SHA: github.String("commit")}, &github.Response{}, nil)
+	gitService.UpdateRefReturns(&github.Reference{}, &github.Response{}, nil)
+	return gitService
+}
@@ -0,0 +1,150 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/google/go-github/v29/github"
+	"gopkg.in/yaml.v3"
+
+	"github.com/jace-ys/mobydick-action/bin/pkg/worker"
+)
+
+const (
+	CoverageCovered       = "covered"
+	CoverageUncovered     = "uncovered"
+	CoverageUnconfigured  = "unconfigured"
+	CoverageNoDockerfiles = "no-dockerfiles"
+)
+
+type Coverage struct {
+	Repository  string
+	Status      string
+	Directories []string
+	Uncovered   []string
+	Stale       []string
+}
+
+func (am *ActionManager) Coverage(ctx context.Context, filter RepositoryFilter) ([]*Coverage, int, error) {
+	repositories, err := am.ListRepositories(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list repositories: %w", err)
+	}
+
+	var jobs []worker.Job
+	for _, repository := range repositories {
+		jobs = append(jobs, &coverageJob{
+			handler:    am,
+			repository: repository,
+		})
+	}
+
+	results := am.workerPool.Work(ctx, jobs)
+
+	var coverage []*Coverage
+	var failures int
+	for _, result := range results {
+		job := result.Job.(*coverageJob)
+		if result.Err != nil {
+			level.Info(am.logger).Log("event", "coverage.failure", "repository", job.repository.GetName(), "error", result.Err)
+			failures++
+			continue
+		}
+		coverage = append(coverage, job.coverage)
+	}
+
+	sort.Slice(coverage, func(i, j int) bool {
+		return coverage[i].Repository < coverage[j].Repository
+	})
+
+	return coverage, failures, nil
+}
+
+// RepositoryCoverage compares the directories containing Dockerfiles in the
+// repository against the docker entries in its Dependabot config.
+func (am *ActionManager) RepositoryCoverage(ctx context.Context, repository *github.Repository) (*Coverage, error) {
+	dockerfiles, err := am.ListDockerfiles(ctx, repository)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Dockerfiles: %w", err)
+	}
+
+	coverage := &Coverage{Repository: repository.GetName()}
+	for _, directory := range dockerfileDirectories(dockerfiles) {
+		coverage.Directories = append(coverage.Directories, dependabotDirectory(directory))
+	}
+
+	config, err := am.GetDependabotConfig(ctx, repository.GetName(), "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file: %w", err)
+	}
+
+	if config == nil {
+		coverage.Uncovered = coverage.Directories
+		coverage.Status = CoverageUnconfigured
+		if len(coverage.Directories) == 0 {
+			coverage.Status = CoverageNoDockerfiles
+		}
+		return coverage, nil
+	}
+
+	var document yaml.Node
+	err = yaml.Unmarshal(config.Content, &document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Dependabot config: %w", err)
+	}
+
+	var configured []string
+	if len(document.Content) > 0 {
+		configured = dockerDirectories(document.Content[0])
+	}
+
+	coverage.Uncovered = difference(coverage.Directories, configured)
+	coverage.Stale = difference(configured, coverage.Directories)
+
+	switch {
+	case len(coverage.Uncovered) > 0:
+		coverage.Status = CoverageUncovered
+	case len(coverage.Directories) == 0:
+		coverage.Status = CoverageNoDockerfiles
+	default:
+		coverage.Status = CoverageCovered
+	}
+
+	return coverage, nil
+}
+
+func difference(a, b []string) []string {
+	exclude := make(map[string]bool)
+	for _, item := range b {
+		exclude[item] = true
+	}
+
+	var result []string
+	for _, item := range a {
+		if !exclude[item] {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+type coverageJob struct {
+	handler    *ActionManager
+	repository *github.Repository
+	coverage   *Coverage
+}
+
+func (job *coverageJob) Repository() *github.Repository {
+	return job.repository
+}
+
+func (job *coverageJob) Process(ctx context.Context) error {
+	coverage, err := job.handler.RepositoryCoverage(ctx, job.repository)
+	if err != nil {
+		return err
+	}
+	job.coverage = coverage
+	return nil
+}
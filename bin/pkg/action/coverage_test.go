@@ -0,0 +1,92 @@
+package action_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/go-github/v29/github"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jace-ys/mobydick-action/bin/pkg/action"
+	"github.com/jace-ys/mobydick-action/bin/pkg/action/actionfakes"
+	"github.com/jace-ys/mobydick-action/bin/pkg/worker"
+)
+
+func TestCoverage(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logger := log.NewNopLogger()
+	pullRequestsService := new(actionfakes.FakePullRequestsService)
+	workerPool := worker.NewWorkerPool(1)
+	repository := fakeRepositories(1)[0]
+
+	t.Run("Unconfigured", func(t *testing.T) {
+		repositoriesService := new(actionfakes.FakeRepositoriesService)
+		repositoriesService.GetContentsReturns(nil, nil, fakeResponse(http.StatusNotFound), fmt.Errorf("not found"))
+
+		gitService := new(actionfakes.FakeGitService)
+		gitService.GetTreeReturns(fakeTree("Dockerfile"), &github.Response{}, nil)
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, nil, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+		coverage, err := actionManager.RepositoryCoverage(ctx, repository)
+
+		assert.NoError(t, err)
+		assert.Equal(t, action.CoverageUnconfigured, coverage.Status)
+		assert.Equal(t, []string{"/"}, coverage.Uncovered)
+	})
+
+	t.Run("Uncovered", func(t *testing.T) {
+		repositoriesService := new(actionfakes.FakeRepositoriesService)
+		repositoriesService.GetContentsReturns(fakeContent("version: 2\nupdates:\n  - package-ecosystem: docker\n    directory: /old\n"), nil, &github.Response{}, nil)
+
+		gitService := new(actionfakes.FakeGitService)
+		gitService.GetTreeReturns(fakeTree("Dockerfile", "docker/api/Dockerfile"), &github.Response{}, nil)
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, nil, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+		coverage, err := actionManager.RepositoryCoverage(ctx, repository)
+
+		assert.NoError(t, err)
+		assert.Equal(t, action.CoverageUncovered, coverage.Status)
+		assert.Equal(t, []string{"/", "/docker/api"}, coverage.Uncovered)
+		assert.Equal(t, []string{"/old"}, coverage.Stale)
+	})
+
+	t.Run("Covered", func(t *testing.T) {
+		repositoriesService := new(actionfakes.FakeRepositoriesService)
+		repositoriesService.GetContentsReturns(fakeContent("version: 2\nupdates:\n  - package-ecosystem: docker\n    directory: /docker/api/\n"), nil, &github.Response{}, nil)
+
+		gitService := new(actionfakes.FakeGitService)
+		gitService.GetTreeReturns(fakeTree("docker/api/Dockerfile"), &github.Response{}, nil)
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, nil, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+		coverage, err := actionManager.RepositoryCoverage(ctx, repository)
+
+		assert.NoError(t, err)
+		assert.Equal(t, action.CoverageCovered, coverage.Status)
+		assert.Empty(t, coverage.Uncovered)
+		assert.Empty(t, coverage.Stale)
+	})
+	t.Run("CoveredYaml", func(t *testing.T) {
+		repositoriesService := new(actionfakes.FakeRepositoriesService)
+		repositoriesService.GetContentsStub = func(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error) {
+			if path == ".github/dependabot.yaml" {
+				return fakeContent("version: 2\nupdates:\n  - package-ecosystem: docker\n    directory: /\n"), nil, &github.Response{}, nil
+			}
+			return nil, nil, fakeResponse(http.StatusNotFound), fmt.Errorf("not found")
+		}
+
+		gitService := new(actionfakes.FakeGitService)
+		gitService.GetTreeReturns(fakeTree("Dockerfile"), &github.Response{}, nil)
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, nil, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+		coverage, err := actionManager.RepositoryCoverage(ctx, repository)
+
+		assert.NoError(t, err)
+		assert.Equal(t, action.CoverageCovered, coverage.Status)
+		assert.Empty(t, coverage.Uncovered)
+	})
+}
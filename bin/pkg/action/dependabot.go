@@ -0,0 +1,170 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DependabotPath is where a new Dependabot config is committed.
+const DependabotPath = ".github/dependabot.yml"
+
+// DependabotPaths are the locations of the Dependabot config that are checked,
+// as Dependabot accepts either extension.
+var DependabotPaths = []string{DependabotPath, ".github/dependabot.yaml"}
+
+var DependabotIntervals = []string{"daily", "weekly", "monthly"}
+
+type DependabotConfig struct {
+	Version int                `yaml:"version"`
+	Updates []DependabotUpdate `yaml:"updates"`
+}
+
+type DependabotUpdate struct {
+	PackageEcosystem string             `yaml:"package-ecosystem"`
+	Directory        string             `yaml:"directory"`
+	Schedule         DependabotSchedule `yaml:"schedule"`
+}
+
+type DependabotSchedule struct {
+	Interval string `yaml:"interval"`
+}
+
+func NewDependabotConfig(directories []string, interval string) *DependabotConfig {
+	config := &DependabotConfig{Version: 2}
+	for _, directory := range directories {
+		config.Updates = append(config.Updates, DependabotUpdate{
+			PackageEcosystem: "docker",
+			Directory:        dependabotDirectory(directory),
+			Schedule:         DependabotSchedule{Interval: interval},
+		})
+	}
+	return config
+}
+
+func (c *DependabotConfig) Marshal() ([]byte, error) {
+	content, err := marshalYAML(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Dependabot config: %w", err)
+	}
+	return content, nil
+}
+
+// dependabotDirectory converts a repository-relative directory into the
+// root-relative form used by Dependabot.
+func dependabotDirectory(directory string) string {
+	return path.Join("/", directory)
+}
+
+// MergeDependabotConfig adds docker entries for the directories not yet covered
+// by an existing Dependabot config, leaving every other entry and comment as it
+// is. It returns the merged config along with the directories that were added.
+func MergeDependabotConfig(content []byte, directories []string, interval string) ([]byte, []string, error) {
+	var document yaml.Node
+	err := yaml.Unmarshal(content, &document)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse Dependabot config: %w", err)
+	}
+
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("Dependabot config must be a mapping")
+	}
+	root := document.Content[0]
+
+	covered := make(map[string]bool)
+	for _, directory := range dockerDirectories(root) {
+		covered[directory] = true
+	}
+
+	var missing []string
+	for _, directory := range directories {
+		if !covered[dependabotDirectory(directory)] {
+			missing = append(missing, directory)
+		}
+	}
+	if len(missing) == 0 {
+		return content, nil, nil
+	}
+
+	added := NewDependabotConfig(missing, interval)
+	items, err := marshalYAML(added.Updates)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal Dependabot config: %w", err)
+	}
+
+	var addedDirectories []string
+	for _, update := range added.Updates {
+		addedDirectories = append(addedDirectories, update.Directory)
+	}
+
+	key, updates := mappingEntry(root, "updates")
+	if updates != nil {
+		merged, ok := insertIntoSequence(content, key, updates, items)
+		if ok {
+			return merged, addedDirectories, nil
+		}
+	}
+
+	// Fall back to re-encoding the document if the updates can't be appended to in place
+	var itemsNode yaml.Node
+	err = yaml.Unmarshal(items, &itemsNode)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if updates == nil {
+		root.Content = append(root.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "updates"},
+			&yaml.Node{Kind: yaml.SequenceNode},
+		)
+		updates = root.Content[len(root.Content)-1]
+	}
+	if updates.Kind != yaml.SequenceNode {
+		return nil, nil, fmt.Errorf("Dependabot config updates must be a list")
+	}
+	updates.Style = 0
+	updates.Content = append(updates.Content, itemsNode.Content[0].Content...)
+
+	merged, err := marshalYAML(&document)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal Dependabot config: %w", err)
+	}
+
+	return merged, addedDirectories, nil
+}
+
+// dockerDirectories returns the directories of the docker entries in a parsed
+// Dependabot config, in the root-relative form used by Dependabot.
+func dockerDirectories(root *yaml.Node) []string {
+	updates := mappingValue(root, "updates")
+	if updates == nil || updates.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	var directories []string
+	for _, update := range updates.Content {
+		ecosystem := mappingValue(update, "package-ecosystem")
+		directory := mappingValue(update, "directory")
+		if ecosystem != nil && ecosystem.Value == "docker" && directory != nil {
+			directories = append(directories, dependabotDirectory(directory.Value))
+		}
+	}
+	return directories
+}
+
+// GetDependabotConfig returns the Dependabot config of the repository on ref,
+// or nil if it has none.
+func (am *ActionManager) GetDependabotConfig(ctx context.Context, repository, ref string) (*RepositoryFile, error) {
+	for _, path := range DependabotPaths {
+		file, err := am.GetFile(ctx, repository, path, ref)
+		if err != nil {
+			return nil, err
+		}
+		if file != nil {
+			return file, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,89 @@
+package action_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jace-ys/mobydick-action/bin/pkg/action"
+)
+
+func TestDependabotConfig(t *testing.T) {
+	content, err := action.NewDependabotConfig([]string{".", "docker/api"}, "daily").Marshal()
+	require.NoError(t, err)
+
+	assert.Equal(t, `version: 2
+updates:
+  - package-ecosystem: docker
+    directory: /
+    schedule:
+      interval: daily
+  - package-ecosystem: docker
+    directory: /docker/api
+    schedule:
+      interval: daily
+`, string(content))
+}
+
+func TestMergeDependabotConfig(t *testing.T) {
+	existing := `# Dependabot config
+version: 2
+updates:
+  # Go modules
+  - package-ecosystem: gomod
+    directory: "/"
+    schedule:
+      interval: daily # keep daily
+
+  - package-ecosystem: docker
+    directory: "/docker/api"
+    schedule:
+      interval: weekly
+
+# Trailing comment
+`
+
+	t.Run("Merge", func(t *testing.T) {
+		content, added, err := action.MergeDependabotConfig([]byte(existing), []string{".", "docker/api"}, "daily")
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"/"}, added)
+		assert.Equal(t, `# Dependabot config
+version: 2
+updates:
+  # Go modules
+  - package-ecosystem: gomod
+    directory: "/"
+    schedule:
+      interval: daily # keep daily
+
+  - package-ecosystem: docker
+    directory: "/docker/api"
+    schedule:
+      interval: weekly
+  - package-ecosystem: docker
+    directory: /
+    schedule:
+      interval: daily
+
+# Trailing comment
+`, string(content))
+	})
+
+	t.Run("Covered", func(t *testing.T) {
+		content, added, err := action.MergeDependabotConfig([]byte(existing), []string{"docker/api"}, "daily")
+		require.NoError(t, err)
+
+		assert.Empty(t, added)
+		assert.Equal(t, existing, string(content))
+	})
+
+	t.Run("NoUpdates", func(t *testing.T) {
+		content, added, err := action.MergeDependabotConfig([]byte("version: 2\n"), []string{"."}, "daily")
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"/"}, added)
+		assert.Contains(t, string(content), "package-ecosystem: docker")
+	})
+}
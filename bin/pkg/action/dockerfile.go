@@ -0,0 +1,57 @@
+package action
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/google/go-github/v29/github"
+)
+
+func (am *ActionManager) ListDockerfiles(ctx context.Context, repository *github.Repository) ([]string, error) {
+	tree, response, err := am.gitService.GetTree(ctx, am.organisation, repository.GetName(), repository.GetDefaultBranch(), true)
+	if err != nil {
+		// Empty repositories have no tree to list
+		if response != nil && response.Response != nil && response.StatusCode == http.StatusConflict {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if tree.GetTruncated() {
+		level.Info(am.logger).Log("event", "list_dockerfiles.truncated", "repository", repository.GetName())
+	}
+
+	var dockerfiles []string
+	for _, entry := range tree.Entries {
+		if entry.GetType() == "blob" && isDockerfile(entry.GetPath()) {
+			dockerfiles = append(dockerfiles, entry.GetPath())
+		}
+	}
+
+	sort.Strings(dockerfiles)
+	return dockerfiles, nil
+}
+
+// isDockerfile matches the same files as the action's **/*Dockerfile* glob.
+func isDockerfile(file string) bool {
+	return strings.Contains(path.Base(file), "Dockerfile")
+}
+
+func dockerfileDirectories(dockerfiles []string) []string {
+	seen := make(map[string]bool)
+	var directories []string
+	for _, dockerfile := range dockerfiles {
+		directory := path.Dir(dockerfile)
+		if !seen[directory] {
+			seen[directory] = true
+			directories = append(directories, directory)
+		}
+	}
+
+	sort.Strings(directories)
+	return directories
+}
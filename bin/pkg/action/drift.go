@@ -0,0 +1,237 @@
+package action
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/google/go-github/v29/github"
+	"gopkg.in/yaml.v3"
+
+	"github.com/jace-ys/mobydick-action/bin/pkg/worker"
+)
+
+const (
+	DriftInSync  = "in-sync"
+	DriftDrifted = "drifted"
+	DriftMissing = "missing"
+)
+
+type Drift struct {
+	Repository  string
+	Status      string
+	Differences []*Difference
+}
+
+// Difference is a value that differs between the installed workflow and the
+// canonical one at a path such as jobs.mobydick.steps[0].uses. Installed or
+// Canonical is empty when the value is only found in the other workflow.
+type Difference struct {
+	Path      string
+	Installed string
+	Canonical string
+}
+
+func (am *ActionManager) Drift(ctx context.Context, filter RepositoryFilter) ([]*Drift, int, error) {
+	repositories, err := am.ListRepositories(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list repositories: %w", err)
+	}
+
+	var jobs []worker.Job
+	for _, repository := range repositories {
+		jobs = append(jobs, &driftJob{
+			handler:    am,
+			repository: repository,
+		})
+	}
+
+	results := am.workerPool.Work(ctx, jobs)
+
+	var drift []*Drift
+	var failures int
+	for _, result := range results {
+		job := result.Job.(*driftJob)
+		if result.Err != nil {
+			level.Info(am.logger).Log("event", "drift.failure", "repository", job.repository.GetName(), "error", result.Err)
+			failures++
+			continue
+		}
+		drift = append(drift, job.drift)
+	}
+
+	sort.Slice(drift, func(i, j int) bool {
+		return drift[i].Repository < drift[j].Repository
+	})
+
+	return drift, failures, nil
+}
+
+// RepositoryDrift compares the workflow installed in the repository with the
+// workflow rendered for it, ignoring formatting, comments and key order.
+func (am *ActionManager) RepositoryDrift(ctx context.Context, repository *github.Repository) (*Drift, error) {
+	dockerfiles, err := am.ListDockerfiles(ctx, repository)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Dockerfiles: %w", err)
+	}
+
+	workflowFile, err := am.RenderWorkflow(repository, dockerfiles)
+	if err != nil {
+		return nil, err
+	}
+
+	installed, err := am.GetFile(ctx, repository.GetName(), workflowFile.Path, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file: %w", err)
+	}
+
+	drift := &Drift{Repository: repository.GetName(), Status: DriftMissing}
+	if installed == nil {
+		return drift, nil
+	}
+
+	drift.Differences, err = WorkflowDifferences(installed.Content, workflowFile.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	drift.Status = DriftInSync
+	if len(drift.Differences) > 0 {
+		drift.Status = DriftDrifted
+	}
+
+	return drift, nil
+}
+
+// WorkflowDifferences returns the values that differ between two workflows once
+// parsed, so that whitespace, comments and key order don't count.
+func WorkflowDifferences(installed, canonical []byte) ([]*Difference, error) {
+	var a, b interface{}
+	err := yaml.Unmarshal(installed, &a)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse installed workflow: %w", err)
+	}
+
+	err = yaml.Unmarshal(canonical, &b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse canonical workflow: %w", err)
+	}
+
+	return compareValues("", a, b), nil
+}
+
+func compareValues(path string, a, b interface{}) []*Difference {
+	aMap, aIsMap := normaliseMapping(a)
+	bMap, bIsMap := normaliseMapping(b)
+	if aIsMap && bIsMap {
+		var keys []string
+		for key := range aMap {
+			keys = append(keys, key)
+		}
+		for key := range bMap {
+			if _, ok := aMap[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		var differences []*Difference
+		for _, key := range keys {
+			differences = append(differences, compareEntry(joinPath(path, key), aMap, bMap, key)...)
+		}
+		return differences
+	}
+
+	aList, aIsList := a.([]interface{})
+	bList, bIsList := b.([]interface{})
+	if aIsList && bIsList {
+		var differences []*Difference
+		for i := 0; i < len(aList) || i < len(bList); i++ {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(aList):
+				differences = append(differences, &Difference{Path: itemPath, Canonical: formatValue(bList[i])})
+			case i >= len(bList):
+				differences = append(differences, &Difference{Path: itemPath, Installed: formatValue(aList[i])})
+			default:
+				differences = append(differences, compareValues(itemPath, aList[i], bList[i])...)
+			}
+		}
+		return differences
+	}
+
+	if reflect.DeepEqual(a, b) {
+		return nil
+	}
+	return []*Difference{{Path: path, Installed: formatValue(a), Canonical: formatValue(b)}}
+}
+
+func compareEntry(path string, a, b map[string]interface{}, key string) []*Difference {
+	aValue, inA := a[key]
+	bValue, inB := b[key]
+	switch {
+	case !inA:
+		return []*Difference{{Path: path, Canonical: formatValue(bValue)}}
+	case !inB:
+		return []*Difference{{Path: path, Installed: formatValue(aValue)}}
+	default:
+		return compareValues(path, aValue, bValue)
+	}
+}
+
+func normaliseMapping(value interface{}) (map[string]interface{}, bool) {
+	switch mapping := value.(type) {
+	case map[string]interface{}:
+		return mapping, true
+	case map[interface{}]interface{}:
+		normalised := make(map[string]interface{}, len(mapping))
+		for key, value := range mapping {
+			normalised[fmt.Sprint(key)] = value
+		}
+		return normalised, true
+	default:
+		return nil, false
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// formatValue formats a value on a single line for reporting.
+func formatValue(value interface{}) string {
+	if mapping, ok := value.(map[interface{}]interface{}); ok {
+		value, _ = normaliseMapping(mapping)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+type driftJob struct {
+	handler    *ActionManager
+	repository *github.Repository
+	drift      *Drift
+}
+
+func (job *driftJob) Repository() *github.Repository {
+	return job.repository
+}
+
+func (job *driftJob) Process(ctx context.Context) error {
+	drift, err := job.handler.RepositoryDrift(ctx, job.repository)
+	if err != nil {
+		return err
+	}
+	job.drift = drift
+	return nil
+}
@@ -0,0 +1,105 @@
+package action_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/go-github/v29/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jace-ys/mobydick-action/bin/pkg/action"
+	"github.com/jace-ys/mobydick-action/bin/pkg/action/actionfakes"
+	"github.com/jace-ys/mobydick-action/bin/pkg/worker"
+)
+
+func TestDrift(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logger := log.NewNopLogger()
+	pullRequestsService := new(actionfakes.FakePullRequestsService)
+	workerPool := worker.NewWorkerPool(1)
+	workflowTemplate := fakeWorkflowTemplate(t)
+	repository := fakeRepositories(1)[0]
+
+	gitService := new(actionfakes.FakeGitService)
+	gitService.GetTreeReturns(fakeTree("Dockerfile"), &github.Response{}, nil)
+
+	t.Run("Missing", func(t *testing.T) {
+		repositoriesService := new(actionfakes.FakeRepositoriesService)
+		repositoriesService.GetContentsReturns(nil, nil, fakeResponse(http.StatusNotFound), fmt.Errorf("not found"))
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+		drift, err := actionManager.RepositoryDrift(ctx, repository)
+
+		assert.NoError(t, err)
+		assert.Equal(t, action.DriftMissing, drift.Status)
+	})
+
+	t.Run("InSync", func(t *testing.T) {
+		repositoriesService := new(actionfakes.FakeRepositoriesService)
+		repositoriesService.GetContentsReturns(fakeContent(`# Installed by Mobydick
+jobs:
+  mobydick:
+    steps:
+    - with: {directories: "[.]"}
+      uses: organisation/repository@v1.0.0
+    runs-on: ubuntu-latest
+on: push
+`), nil, &github.Response{}, nil)
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+		drift, err := actionManager.RepositoryDrift(ctx, repository)
+
+		assert.NoError(t, err)
+		assert.Equal(t, action.DriftInSync, drift.Status)
+		assert.Empty(t, drift.Differences)
+	})
+
+	t.Run("Drifted", func(t *testing.T) {
+		repositoriesService := new(actionfakes.FakeRepositoriesService)
+		repositoriesService.GetContentsReturns(fakeContent(`on: [push, pull_request]
+jobs:
+  mobydick:
+    runs-on: self-hosted
+    steps:
+      - uses: organisation/repository@v0.9.0
+        with:
+          directories: "[.]"
+      - run: echo done
+`), nil, &github.Response{}, nil)
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+		drift, err := actionManager.RepositoryDrift(ctx, repository)
+
+		require.NoError(t, err)
+		assert.Equal(t, action.DriftDrifted, drift.Status)
+		assert.Equal(t, []*action.Difference{
+			{Path: "jobs.mobydick.runs-on", Installed: `"self-hosted"`, Canonical: `"ubuntu-latest"`},
+			{Path: "jobs.mobydick.steps[0].uses", Installed: `"organisation/repository@v0.9.0"`, Canonical: `"organisation/repository@v1.0.0"`},
+			{Path: "jobs.mobydick.steps[1]", Installed: `{"run":"echo done"}`},
+			{Path: "on", Installed: `["push","pull_request"]`, Canonical: `"push"`},
+		}, drift.Differences)
+	})
+
+	t.Run("Drift", func(t *testing.T) {
+		repositoriesService := new(actionfakes.FakeRepositoriesService)
+		repositoriesService.ListByOrgReturns([]*github.Repository{
+			{Name: github.String("b")},
+			{Name: github.String("a")},
+		}, &github.Response{}, nil)
+		repositoriesService.GetContentsReturns(nil, nil, fakeResponse(http.StatusNotFound), fmt.Errorf("not found"))
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+		drift, failures, err := actionManager.Drift(ctx, action.RepositoryFilter{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, failures)
+		assert.Equal(t, "a", drift[0].Repository)
+		assert.Equal(t, "b", drift[1].Repository)
+	})
+}
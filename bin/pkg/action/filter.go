@@ -0,0 +1,148 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/google/go-github/v29/github"
+)
+
+// RepositoryFilter selects the repositories in the organisation that commands
+// work on. An empty list of names selects every repository. Team limits them to
+// the repositories of the team with that slug, and Codeowner to those whose
+// CODEOWNERS file assigns one of their Dockerfiles to that owner.
+type RepositoryFilter struct {
+	Private      bool
+	SkipArchived bool
+	SkipForks    bool
+	Names        []string
+	Team         string
+	Codeowner    string
+}
+
+func (f RepositoryFilter) Matches(repository *github.Repository) bool {
+	switch {
+	case f.Private && !repository.GetPrivate():
+		return false
+	case f.SkipArchived && repository.GetArchived():
+		return false
+	case f.SkipForks && repository.GetFork():
+		return false
+	}
+
+	if len(f.Names) == 0 {
+		return true
+	}
+	for _, name := range f.Names {
+		if strings.EqualFold(name, repository.GetName()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (am *ActionManager) ListRepositories(ctx context.Context, filter RepositoryFilter) ([]*github.Repository, error) {
+	var list []*github.Repository
+	var err error
+	if filter.Team != "" {
+		list, err = am.listTeamRepositories(ctx, filter.Team)
+	} else {
+		list, err = am.listOrganisationRepositories(ctx, filter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := am.filterRepositories(list, filter)
+	if filter.Codeowner == "" {
+		return filtered, nil
+	}
+
+	return am.filterCodeowner(ctx, filtered, filter.Codeowner)
+}
+
+func (am *ActionManager) listOrganisationRepositories(ctx context.Context, filter RepositoryFilter) ([]*github.Repository, error) {
+	listType := "all"
+	if filter.Private {
+		listType = "private"
+	}
+
+	opts := &github.RepositoryListByOrgOptions{
+		Type:        listType,
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var list []*github.Repository
+	for {
+		repositories, response, err := am.repositoriesService.ListByOrg(ctx, am.organisation, opts)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, repositories...)
+		if response.NextPage == 0 {
+			break
+		}
+		opts.Page = response.NextPage
+	}
+
+	return list, nil
+}
+
+func (am *ActionManager) listTeamRepositories(ctx context.Context, team string) ([]*github.Repository, error) {
+	opts := &github.ListOptions{PerPage: 100}
+
+	var list []*github.Repository
+	for {
+		repositories, response, err := am.teamsService.ListTeamReposBySlug(ctx, am.organisation, team, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repositories of team %s: %w", team, err)
+		}
+		list = append(list, repositories...)
+		if response.NextPage == 0 {
+			break
+		}
+		opts.Page = response.NextPage
+	}
+
+	return list, nil
+}
+
+func (am *ActionManager) filterCodeowner(ctx context.Context, repositories []*github.Repository, codeowner string) ([]*github.Repository, error) {
+	if !strings.HasPrefix(codeowner, "@") {
+		codeowner = "@" + codeowner
+	}
+
+	var filtered []*github.Repository
+	for _, repository := range repositories {
+		owned, err := am.OwnsDockerfiles(ctx, repository, codeowner)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check CODEOWNERS of %s: %w", repository.GetName(), err)
+		}
+		if owned {
+			filtered = append(filtered, repository)
+		}
+	}
+
+	return filtered, nil
+}
+
+func (am *ActionManager) filterRepositories(repositories []*github.Repository, filter RepositoryFilter) []*github.Repository {
+	var filtered []*github.Repository
+	found := make(map[string]bool)
+	for _, repository := range repositories {
+		if filter.Matches(repository) {
+			filtered = append(filtered, repository)
+			found[strings.ToLower(repository.GetName())] = true
+		}
+	}
+
+	for _, name := range filter.Names {
+		if !found[strings.ToLower(name)] {
+			level.Info(am.logger).Log("event", "list_repositories.not_found", "repository", name)
+		}
+	}
+
+	return filtered
+}
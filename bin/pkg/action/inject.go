@@ -0,0 +1,158 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"gopkg.in/yaml.v3"
+)
+
+// InjectionFile returns the first workflow on ref whose file name matches the
+// glob, with the jobs of the rendered workflow injected into it, or its steps
+// using this action if a job is given.
+func (am *ActionManager) InjectionFile(ctx context.Context, repository, ref, glob, job string, workflowFile *WorkflowFile) (*RepositoryFile, error) {
+	workflows, err := am.ListWorkflows(ctx, repository, ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflows: %w", err)
+	}
+
+	var target string
+	for _, workflow := range workflows {
+		matched, err := path.Match(glob, path.Base(workflow))
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			target = workflow
+			break
+		}
+	}
+	if target == "" {
+		return nil, fmt.Errorf("no workflow matches %q", glob)
+	}
+
+	existing, err := am.GetFile(ctx, repository, target, ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file: %w", err)
+	}
+	if existing == nil {
+		return nil, fmt.Errorf("workflow %s not found", target)
+	}
+
+	content, err := InjectWorkflow(existing.Content, workflowFile.Content, job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inject into %s: %w", target, err)
+	}
+
+	return &RepositoryFile{Path: target, Content: content, SHA: existing.SHA, Previous: existing.Content}, nil
+}
+
+// InjectWorkflow appends the jobs of the rendered workflow missing from an
+// existing one, or only the steps of the rendered workflow using this action to
+// the given job of the existing workflow, leaving the formatting and comments
+// around them untouched. Workflows already using this action, or the job in
+// question, are returned as they are.
+func InjectWorkflow(content, rendered []byte, job string) ([]byte, error) {
+	var document, renderedDocument yaml.Node
+	err := yaml.Unmarshal(content, &document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse workflow: %w", err)
+	}
+	err = yaml.Unmarshal(rendered, &renderedDocument)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse rendered workflow: %w", err)
+	}
+
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("workflow must be a mapping")
+	}
+	root := document.Content[0]
+	renderedRoot := renderedDocument.Content[0]
+
+	if job == "" {
+		return injectJobs(content, &document, root, renderedRoot)
+	}
+	return injectSteps(content, &document, root, renderedRoot, job)
+}
+
+func injectJobs(content []byte, document, root, renderedRoot *yaml.Node) ([]byte, error) {
+	if len(actionSteps(root)) > 0 {
+		return content, nil
+	}
+
+	key, jobs := mappingEntry(root, "jobs")
+	if jobs == nil || jobs.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("workflow jobs must be a mapping")
+	}
+
+	// Jobs that were injected before are left alone, even if this action is no longer used by them
+	renderedJobs := mappingValue(renderedRoot, "jobs")
+	added := &yaml.Node{Kind: yaml.MappingNode}
+	for i := 0; i+1 < len(renderedJobs.Content); i += 2 {
+		if mappingValue(jobs, renderedJobs.Content[i].Value) == nil {
+			added.Content = append(added.Content, renderedJobs.Content[i], renderedJobs.Content[i+1])
+		}
+	}
+	if len(added.Content) == 0 {
+		return content, nil
+	}
+
+	entries, err := marshalYAML(added)
+	if err != nil {
+		return nil, err
+	}
+
+	injected, ok := insertIntoMapping(content, key, jobs, entries)
+	if ok {
+		return injected, nil
+	}
+
+	// Fall back to re-encoding the document if the jobs can't be appended to in place
+	jobs.Style = 0
+	jobs.Content = append(jobs.Content, added.Content...)
+	return marshalYAML(document)
+}
+
+func injectSteps(content []byte, document, root, renderedRoot *yaml.Node, job string) ([]byte, error) {
+	jobs := mappingValue(root, "jobs")
+	if jobs == nil || jobs.Kind != yaml.MappingNode || mappingValue(jobs, job) == nil {
+		return nil, fmt.Errorf("workflow has no job named %s", job)
+	}
+
+	for _, step := range actionSteps(root) {
+		if step.Job == job {
+			return content, nil
+		}
+	}
+
+	key, steps := mappingEntry(mappingValue(jobs, job), "steps")
+	if steps == nil || steps.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("job %s steps must be a list", job)
+	}
+
+	renderedJobs := mappingValue(renderedRoot, "jobs")
+	added := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, step := range actionSteps(renderedRoot) {
+		renderedSteps := mappingValue(mappingValue(renderedJobs, step.Job), "steps")
+		added.Content = append(added.Content, renderedSteps.Content[step.Index])
+	}
+	if len(added.Content) == 0 {
+		return nil, fmt.Errorf("rendered workflow has no steps using %s", ActionRepository)
+	}
+
+	items, err := marshalYAML(added)
+	if err != nil {
+		return nil, err
+	}
+
+	injected, ok := insertIntoSequence(content, key, steps, items)
+	if ok {
+		return injected, nil
+	}
+
+	// Fall back to re-encoding the document if the steps can't be appended to in place
+	steps.Style = 0
+	steps.Content = append(steps.Content, added.Content...)
+	return marshalYAML(document)
+}
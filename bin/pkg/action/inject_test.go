@@ -0,0 +1,137 @@
+package action_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/go-github/v29/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jace-ys/mobydick-action/bin/pkg/action"
+	"github.com/jace-ys/mobydick-action/bin/pkg/action/actionfakes"
+	"github.com/jace-ys/mobydick-action/bin/pkg/worker"
+)
+
+const renderedWorkflow = `on: [push, pull_request]
+
+jobs:
+  mobydick:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v2
+      - uses: jace-ys/mobydick-action@v1.0.0
+`
+
+const ciWorkflow = `# Our one CI workflow
+on: push
+
+jobs:
+  # Runs the tests
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v2
+      - run: make test # all of them
+`
+
+func TestInjectWorkflow(t *testing.T) {
+	t.Run("Job", func(t *testing.T) {
+		injected, err := action.InjectWorkflow([]byte(ciWorkflow), []byte(renderedWorkflow), "")
+
+		require.NoError(t, err)
+		assert.Equal(t, ciWorkflow+`  mobydick:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v2
+      - uses: jace-ys/mobydick-action@v1.0.0
+`, string(injected))
+	})
+
+	t.Run("Step", func(t *testing.T) {
+		injected, err := action.InjectWorkflow([]byte(ciWorkflow), []byte(renderedWorkflow), "test")
+
+		require.NoError(t, err)
+		assert.Equal(t, ciWorkflow+"      - uses: jace-ys/mobydick-action@v1.0.0\n", string(injected))
+	})
+
+	t.Run("Idempotent", func(t *testing.T) {
+		for _, job := range []string{"", "test"} {
+			injected, err := action.InjectWorkflow([]byte(ciWorkflow), []byte(renderedWorkflow), job)
+			require.NoError(t, err)
+
+			again, err := action.InjectWorkflow(injected, []byte(renderedWorkflow), job)
+			require.NoError(t, err)
+			assert.Equal(t, string(injected), string(again))
+		}
+	})
+
+	t.Run("AlreadyUsed", func(t *testing.T) {
+		workflow := ciWorkflow + "      - uses: Jace-ys/mobydick-action@v0.9.0\n"
+
+		injected, err := action.InjectWorkflow([]byte(workflow), []byte(renderedWorkflow), "")
+
+		require.NoError(t, err)
+		assert.Equal(t, workflow, string(injected))
+	})
+
+	t.Run("FlowJobs", func(t *testing.T) {
+		injected, err := action.InjectWorkflow([]byte("on: push\njobs: {}\n"), []byte(renderedWorkflow), "")
+
+		require.NoError(t, err)
+		assert.Contains(t, string(injected), "jobs:\n  mobydick:\n    runs-on: ubuntu-latest\n")
+		assert.NoError(t, action.ValidateWorkflow(injected))
+	})
+
+	t.Run("MissingJob", func(t *testing.T) {
+		_, err := action.InjectWorkflow([]byte(ciWorkflow), []byte(renderedWorkflow), "build")
+		assert.Error(t, err)
+	})
+}
+
+func TestInjectionFile(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logger := log.NewNopLogger()
+	workerPool := worker.NewWorkerPool(1)
+	gitService := new(actionfakes.FakeGitService)
+	pullRequestsService := new(actionfakes.FakePullRequestsService)
+	workflowFile := &action.WorkflowFile{Path: ".github/workflows/mobydick.yaml", Content: []byte(renderedWorkflow)}
+
+	repositoriesService := new(actionfakes.FakeRepositoriesService)
+	repositoriesService.GetContentsStub = func(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error) {
+		switch path {
+		case action.WorkflowsDirectory:
+			return nil, []*github.RepositoryContent{
+				{Name: github.String("release.yml"), Path: github.String(".github/workflows/release.yml"), Type: github.String("file")},
+				{Name: github.String("ci.yml"), Path: github.String(".github/workflows/ci.yml"), Type: github.String("file")},
+				{Name: github.String("README.md"), Path: github.String(".github/workflows/README.md"), Type: github.String("file")},
+			}, &github.Response{}, nil
+		case ".github/workflows/ci.yml":
+			return fakeContent(ciWorkflow), nil, &github.Response{}, nil
+		default:
+			return nil, nil, fakeResponse(http.StatusNotFound), fmt.Errorf("not found")
+		}
+	}
+
+	actionManager := action.NewActionManager(ctx, logger, "organisation", false, nil, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+
+	t.Run("Match", func(t *testing.T) {
+		file, err := actionManager.InjectionFile(ctx, "repository", "", "c*.y*ml", "", workflowFile)
+
+		require.NoError(t, err)
+		assert.Equal(t, ".github/workflows/ci.yml", file.Path)
+		assert.Equal(t, "sha", file.SHA)
+		assert.Equal(t, ciWorkflow, string(file.Previous))
+		assert.Contains(t, string(file.Content), "  mobydick:\n")
+	})
+
+	t.Run("NoMatch", func(t *testing.T) {
+		_, err := actionManager.InjectionFile(ctx, "repository", "", "build.yml", "", workflowFile)
+		assert.Error(t, err)
+	})
+}
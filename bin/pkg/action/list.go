@@ -0,0 +1,105 @@
+package action
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/go-github/v29/github"
+)
+
+const (
+	ListFormatTable = "table"
+	ListFormatJSON  = "json"
+	ListFormatNames = "names"
+)
+
+var ListFormats = []string{ListFormatTable, ListFormatJSON, ListFormatNames}
+
+type RepositoryListing struct {
+	Name          string     `json:"name"`
+	Visibility    string     `json:"visibility"`
+	DefaultBranch string     `json:"default_branch"`
+	Archived      bool       `json:"archived"`
+	Fork          bool       `json:"fork"`
+	Language      string     `json:"language"`
+	Topics        []string   `json:"topics"`
+	PushedAt      *time.Time `json:"pushed_at"`
+}
+
+func NewRepositoryListing(repository *github.Repository) *RepositoryListing {
+	listing := &RepositoryListing{
+		Name:          repository.GetName(),
+		Visibility:    "public",
+		DefaultBranch: repository.GetDefaultBranch(),
+		Archived:      repository.GetArchived(),
+		Fork:          repository.GetFork(),
+		Language:      repository.GetLanguage(),
+		Topics:        repository.Topics,
+	}
+	if repository.GetPrivate() {
+		listing.Visibility = "private"
+	}
+	if repository.PushedAt != nil {
+		listing.PushedAt = &repository.PushedAt.Time
+	}
+	return listing
+}
+
+// WriteRepositories writes the repositories sorted by name as a table, a JSON
+// array or one name per line, which other commands take with --repositories-from.
+func WriteRepositories(w io.Writer, repositories []*github.Repository, format string) error {
+	var listings []*RepositoryListing
+	for _, repository := range repositories {
+		listings = append(listings, NewRepositoryListing(repository))
+	}
+	sort.Slice(listings, func(i, j int) bool {
+		return listings[i].Name < listings[j].Name
+	})
+
+	switch format {
+	case ListFormatJSON:
+		if listings == nil {
+			listings = []*RepositoryListing{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(listings)
+
+	case ListFormatNames:
+		for _, listing := range listings {
+			_, err := fmt.Fprintln(w, listing.Name)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case ListFormatTable:
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "NAME\tVISIBILITY\tDEFAULT BRANCH\tARCHIVED\tFORK\tLANGUAGE\tTOPICS\tLAST PUSH")
+		for _, listing := range listings {
+			pushed := "-"
+			if listing.PushedAt != nil {
+				pushed = listing.PushedAt.Format("2006-01-02")
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%t\t%t\t%s\t%s\t%s\n", listing.Name, listing.Visibility, listing.DefaultBranch,
+				listing.Archived, listing.Fork, orDash(listing.Language), orDash(strings.Join(listing.Topics, ",")), pushed)
+		}
+		return tw.Flush()
+
+	default:
+		return fmt.Errorf("unknown list format %q", format)
+	}
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
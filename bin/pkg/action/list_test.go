@@ -0,0 +1,153 @@
+package action_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/go-github/v29/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jace-ys/mobydick-action/bin/pkg/action"
+	"github.com/jace-ys/mobydick-action/bin/pkg/action/actionfakes"
+)
+
+func TestRepositoryFilter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logger := log.NewNopLogger()
+	gitService := new(actionfakes.FakeGitService)
+	pullRequestsService := new(actionfakes.FakePullRequestsService)
+
+	repositoriesService := new(actionfakes.FakeRepositoriesService)
+	repositoriesService.ListByOrgReturns([]*github.Repository{
+		{Name: github.String("api")},
+		{Name: github.String("legacy"), Archived: github.Bool(true)},
+		{Name: github.String("upstream"), Fork: github.Bool(true)},
+	}, &github.Response{}, nil)
+
+	actionManager := action.NewActionManager(ctx, logger, "organisation", false, nil, nil, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+
+	testCases := []struct {
+		name     string
+		filter   action.RepositoryFilter
+		expected []string
+	}{
+		{name: "All", filter: action.RepositoryFilter{}, expected: []string{"api", "legacy", "upstream"}},
+		{name: "SkipArchived", filter: action.RepositoryFilter{SkipArchived: true}, expected: []string{"api", "upstream"}},
+		{name: "SkipForks", filter: action.RepositoryFilter{SkipForks: true}, expected: []string{"api", "legacy"}},
+		{name: "Names", filter: action.RepositoryFilter{Names: []string{"API", "legacy", "unknown"}}, expected: []string{"api", "legacy"}},
+		{name: "NamesSkipArchived", filter: action.RepositoryFilter{Names: []string{"legacy"}, SkipArchived: true}, expected: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repositories, err := actionManager.ListRepositories(ctx, tc.filter)
+			require.NoError(t, err)
+
+			var names []string
+			for _, repository := range repositories {
+				names = append(names, repository.GetName())
+			}
+			assert.Equal(t, tc.expected, names)
+		})
+	}
+
+	t.Run("Team", func(t *testing.T) {
+		teamsService := new(actionfakes.FakeTeamsService)
+		teamsService.ListTeamReposBySlugReturnsOnCall(0, []*github.Repository{{Name: github.String("api")}}, &github.Response{NextPage: 2}, nil)
+		teamsService.ListTeamReposBySlugReturnsOnCall(1, []*github.Repository{{Name: github.String("legacy"), Archived: github.Bool(true)}}, &github.Response{}, nil)
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, nil, nil, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService, Teams: teamsService})
+		repositories, err := actionManager.ListRepositories(ctx, action.RepositoryFilter{Team: "backend", SkipArchived: true})
+
+		require.NoError(t, err)
+		require.Len(t, repositories, 1)
+		assert.Equal(t, "api", repositories[0].GetName())
+
+		_, org, slug, opts := teamsService.ListTeamReposBySlugArgsForCall(1)
+		assert.Equal(t, "organisation", org)
+		assert.Equal(t, "backend", slug)
+		assert.Equal(t, 2, opts.Page)
+	})
+
+	t.Run("Codeowner", func(t *testing.T) {
+		gitService := new(actionfakes.FakeGitService)
+		gitService.GetTreeReturns(fakeTree("Dockerfile"), &github.Response{}, nil)
+
+		repositoriesService := new(actionfakes.FakeRepositoriesService)
+		repositoriesService.ListByOrgReturns([]*github.Repository{
+			{Name: github.String("api")},
+			{Name: github.String("web")},
+		}, &github.Response{}, nil)
+		repositoriesService.GetContentsStub = func(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error) {
+			if repo == "api" {
+				return fakeContent("Dockerfile @org/backend\n"), nil, &github.Response{}, nil
+			}
+			return fakeContent("* @org/frontend\n"), nil, &github.Response{}, nil
+		}
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, nil, nil, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+		repositories, err := actionManager.ListRepositories(ctx, action.RepositoryFilter{Codeowner: "org/backend"})
+
+		require.NoError(t, err)
+		require.Len(t, repositories, 1)
+		assert.Equal(t, "api", repositories[0].GetName())
+	})
+}
+
+func TestWriteRepositories(t *testing.T) {
+	repositories := []*github.Repository{
+		{
+			Name:          github.String("web"),
+			DefaultBranch: github.String("main"),
+			Fork:          github.Bool(true),
+		},
+		{
+			Name:          github.String("api"),
+			Private:       github.Bool(true),
+			DefaultBranch: github.String("master"),
+			Language:      github.String("Go"),
+			Topics:        []string{"docker", "go"},
+			PushedAt:      &github.Timestamp{Time: time.Date(2020, 4, 1, 12, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	t.Run("Table", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := action.WriteRepositories(&buf, repositories, action.ListFormatTable)
+
+		require.NoError(t, err)
+		assert.Equal(t, `NAME  VISIBILITY  DEFAULT BRANCH  ARCHIVED  FORK   LANGUAGE  TOPICS     LAST PUSH
+api   private     master          false     false  Go        docker,go  2020-04-01
+web   public      main            false     true   -         -          -
+`, buf.String())
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := action.WriteRepositories(&buf, repositories, action.ListFormatJSON)
+		require.NoError(t, err)
+
+		var listings []*action.RepositoryListing
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &listings))
+		assert.Equal(t, "api", listings[0].Name)
+		assert.Equal(t, "private", listings[0].Visibility)
+		assert.Equal(t, []string{"docker", "go"}, listings[0].Topics)
+		assert.True(t, listings[1].Fork)
+		assert.Nil(t, listings[1].PushedAt)
+	})
+
+	t.Run("Names", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := action.WriteRepositories(&buf, repositories, action.ListFormatNames)
+
+		require.NoError(t, err)
+		assert.Equal(t, "api\nweb\n", buf.String())
+	})
+}
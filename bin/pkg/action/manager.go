@@ -3,7 +3,11 @@ package action
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
@@ -15,16 +19,80 @@ import (
 //counterfeiter:generate . RepositoriesService
 type RepositoriesService interface {
 	ListByOrg(ctx context.Context, org string, opts *github.RepositoryListByOrgOptions) ([]*github.Repository, *github.Response, error)
+	GetContents(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error)
 	CreateFile(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentFileOptions) (*github.RepositoryContentResponse, *github.Response, error)
+	UpdateFile(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentFileOptions) (*github.RepositoryContentResponse, *github.Response, error)
+	GetLatestRelease(ctx context.Context, owner, repo string) (*github.RepositoryRelease, *github.Response, error)
+	GetCommitSHA1(ctx context.Context, owner, repo, ref, lastSHA string) (string, *github.Response, error)
+}
+
+//counterfeiter:generate . GitService
+type GitService interface {
+	GetTree(ctx context.Context, owner string, repo string, sha string, recursive bool) (*github.Tree, *github.Response, error)
+	GetRef(ctx context.Context, owner string, repo string, ref string) (*github.Reference, *github.Response, error)
+	CreateRef(ctx context.Context, owner string, repo string, ref *github.Reference) (*github.Reference, *github.Response, error)
+	UpdateRef(ctx context.Context, owner string, repo string, ref *github.Reference, force bool) (*github.Reference, *github.Response, error)
+	GetCommit(ctx context.Context, owner string, repo string, sha string) (*github.Commit, *github.Response, error)
+	CreateBlob(ctx context.Context, owner string, repo string, blob *github.Blob) (*github.Blob, *github.Response, error)
+	CreateTree(ctx context.Context, owner string, repo string, baseTree string, entries []github.TreeEntry) (*github.Tree, *github.Response, error)
+	CreateCommit(ctx context.Context, owner string, repo string, commit *github.Commit) (*github.Commit, *github.Response, error)
+}
+
+//counterfeiter:generate . PullRequestsService
+type PullRequestsService interface {
+	List(ctx context.Context, owner string, repo string, opts *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error)
+	Create(ctx context.Context, owner string, repo string, pull *github.NewPullRequest) (*github.PullRequest, *github.Response, error)
+}
+
+//counterfeiter:generate . TeamsService
+type TeamsService interface {
+	ListTeamReposBySlug(ctx context.Context, org, slug string, opts *github.ListOptions) ([]*github.Repository, *github.Response, error)
+}
+
+type DistributeOptions struct {
+	Filter             RepositoryFilter
+	DependabotSchedule string
+	PullRequest        bool
+	CommitMessage      *CommitMessage
+	Branch             string
+	Author             *github.CommitAuthor
+	Committer          *github.CommitAuthor
+	Signer             Signer
+	RequireSigning     bool
+	InjectInto         string
+	InjectJob          string
+	AllowDuplicates    bool
+	Waves              []float64
+	WaveSeed           string
+	MaxFailureRatio    float64
+	BeforeWave         WaveHook
+	VerifyRuns         bool
+	RunTimeout         time.Duration
+	RunPollInterval    time.Duration
+	MaxRunFailureRatio float64
 }
 
 type ActionManager struct {
 	logger              log.Logger
 	organisation        string
 	dryRun              bool
-	workflowFile        *WorkflowFile
+	workflowTemplate    *WorkflowTemplate
 	workerPool          *worker.WorkerPool
 	repositoriesService RepositoriesService
+	gitService          GitService
+	pullRequestsService PullRequestsService
+	teamsService        TeamsService
+	actionsService      ActionsService
+}
+
+// Services are the GitHub APIs used by the ActionManager. Services that a
+// command doesn't use can be left nil.
+type Services struct {
+	Repositories RepositoriesService
+	Git          GitService
+	PullRequests PullRequestsService
+	Teams        TeamsService
+	Actions      ActionsService
 }
 
 func NewActionManager(
@@ -32,110 +100,498 @@ func NewActionManager(
 	logger log.Logger,
 	organisation string,
 	dryRun bool,
-	workflowFile *WorkflowFile,
+	workflowTemplate *WorkflowTemplate,
 	workerPool *worker.WorkerPool,
-	repositories RepositoriesService,
+	services Services,
 ) *ActionManager {
 	return &ActionManager{
 		logger:              logger,
 		organisation:        organisation,
 		dryRun:              dryRun,
-		workflowFile:        workflowFile,
+		workflowTemplate:    workflowTemplate,
 		workerPool:          workerPool,
-		repositoriesService: repositories,
+		repositoriesService: services.Repositories,
+		gitService:          services.Git,
+		pullRequestsService: services.PullRequests,
+		teamsService:        services.Teams,
+		actionsService:      services.Actions,
 	}
 }
 
-func (am *ActionManager) Distribute(ctx context.Context, private bool) (int, int, error) {
-	repositories, err := am.ListRepositories(ctx, private)
+func (am *ActionManager) Distribute(ctx context.Context, opts DistributeOptions) (*Report, error) {
+	err := am.workflowTemplate.Lint()
 	if err != nil {
-		return 0, 0, fmt.Errorf("failed to list repositories: %w", err)
+		return nil, fmt.Errorf("invalid workflow template: %w", err)
 	}
 
+	repositories, err := am.ListRepositories(ctx, opts.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repositories: %w", err)
+	}
+
+	report := &Report{DryRun: am.dryRun}
+	defer report.sort()
+
+	// The circuit breaker counts failures across all the waves of a run
+	am.workerPool.Reset()
+
+	if len(opts.Waves) == 0 {
+		am.distributeWave(ctx, report, repositories, 0, opts)
+		return report, am.workerPool.Err()
+	}
+
+	waves := SplitWaves(OrderRepositories(repositories, opts.WaveSeed), opts.Waves)
+	for i, wave := range waves {
+		number := i + 1
+		if i > 0 && opts.BeforeWave != nil {
+			err := opts.BeforeWave(ctx, number, len(waves), wave)
+			if err != nil {
+				report.abort(waves[i:], number, err)
+				return report, fmt.Errorf("rollout stopped before wave %d of %d: %w", number, len(waves), err)
+			}
+		}
+
+		failures := am.distributeWave(ctx, report, wave, number, opts)
+		level.Info(am.logger).Log("event", "distribute.wave", "wave", number, "waves", len(waves), "repositories", len(wave), "failures", failures)
+
+		if err := am.workerPool.Err(); err != nil {
+			report.abort(waves[number:], number+1, err)
+			return report, err
+		}
+
+		ratio := float64(failures) / float64(len(wave))
+		if ratio > opts.MaxFailureRatio && number < len(waves) {
+			err := fmt.Errorf("%d of %d repositories failed", failures, len(wave))
+			report.abort(waves[number:], number+1, err)
+			return report, fmt.Errorf("rollout stopped after wave %d of %d: %w", number, len(waves), err)
+		}
+
+		runFailures, runs := report.runFailures(number)
+		if runs > 0 && float64(runFailures)/float64(runs) > opts.MaxRunFailureRatio && number < len(waves) {
+			err := fmt.Errorf("workflow runs did not pass in %d of %d repositories", runFailures, runs)
+			report.abort(waves[number:], number+1, err)
+			return report, fmt.Errorf("rollout stopped after wave %d of %d: %w", number, len(waves), err)
+		}
+	}
+
+	return report, nil
+}
+
+// distributeWave distributes to the repositories of one wave, labelled with its
+// number in the report, and returns the number of repositories that failed.
+func (am *ActionManager) distributeWave(ctx context.Context, report *Report, repositories []*github.Repository, wave int, opts DistributeOptions) int {
 	var jobs []worker.Job
 	for _, repository := range repositories {
-		jobs = append(jobs, &createFileJob{
+		jobs = append(jobs, &distributeJob{
 			handler:    am,
-			repository: *repository.Name,
-			path:       am.workflowFile.Path,
-			content:    am.workflowFile.Content,
+			repository: repository,
+			opts:       opts,
 		})
 	}
 
 	results := am.workerPool.Work(ctx, jobs)
 
-	var success []worker.Result
-	var failures []worker.Result
+	var failures int
 	for _, result := range results {
-		if result.Err != nil {
-			failures = append(failures, result)
-		} else {
-			success = append(success, result)
+		job := result.Job.(*distributeJob)
+		if result.Aborted {
+			report.Repositories = append(report.Repositories, &RepositoryReport{
+				Repository: job.repository.GetName(),
+				Status:     StatusAborted,
+				Error:      result.Err.Error(),
+				Wave:       wave,
+			})
+			continue
+		}
+		completed := result.Completed
+		var runErr *runError
+		if result.Err != nil && !errors.As(result.Err, &runErr) {
+			report.Repositories = append(report.Repositories, &RepositoryReport{
+				Repository:  job.repository.GetName(),
+				Status:      StatusFailed,
+				Error:       result.Err.Error(),
+				Wave:        wave,
+				CompletedAt: &completed,
+			})
+			failures++
+			continue
+		}
+		job.report.Wave = wave
+		job.report.CompletedAt = &completed
+		report.Repositories = append(report.Repositories, job.report)
+	}
+
+	return failures
+}
+
+// DistributeRepository commits the workflow, and the Dependabot config if
+// requested, to the repository, leaving out the files that are unchanged. In
+// dry-run mode, the report includes a diff of each file instead.
+func (am *ActionManager) DistributeRepository(ctx context.Context, repository *github.Repository, opts DistributeOptions) (*RepositoryReport, error) {
+	dockerfiles, err := am.ListDockerfiles(ctx, repository)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Dockerfiles: %w", err)
+	}
+
+	workflowFile, err := am.RenderWorkflow(repository, dockerfiles)
+	if err != nil {
+		return nil, err
+	}
+
+	commit, err := am.commitOptions(repository, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Files are read from the branch being committed to, or the base branch of the pull request
+	ref := commit.Branch
+	if commit.PullRequest {
+		ref = ""
+	}
+
+	file, err := am.workflowRepositoryFile(ctx, repository.GetName(), ref, workflowFile, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RepositoryReport{Repository: repository.GetName()}
+
+	// Running the action from a second workflow would only run it twice on every push
+	uses, err := am.FindActionUses(ctx, repository.GetName(), ref, file.Path)
+	if err != nil {
+		return nil, err
+	}
+	for _, use := range uses {
+		report.ActionUses = append(report.ActionUses, use.String())
+	}
+	if len(uses) > 0 {
+		level.Info(am.logger).Log("event", "distribute_repository.action_uses", "repository", repository.GetName(), "uses", strings.Join(report.ActionUses, ","))
+		if !opts.AllowDuplicates {
+			report.Status = StatusSkipped
+			return report, nil
+		}
+	}
+
+	files := []*RepositoryFile{file}
+
+	if opts.DependabotSchedule != "" && len(dockerfiles) > 0 {
+		dependabotFile, err := am.DependabotFile(ctx, repository.GetName(), ref, dockerfileDirectories(dockerfiles), opts.DependabotSchedule)
+		if err != nil {
+			return nil, err
+		}
+		if dependabotFile != nil {
+			files = append(files, dependabotFile)
+		}
+	}
+
+	var changed []*RepositoryFile
+	for _, file := range files {
+		fileReport := &FileReport{Path: file.Path, Status: fileStatus(file, am.dryRun)}
+		if fileReport.Status == StatusUnchanged {
+			report.Files = append(report.Files, fileReport)
+			continue
+		}
+
+		if am.dryRun {
+			fileReport.Diff, err = unifiedDiff(file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to diff file: %w", err)
+			}
+		}
+		report.Files = append(report.Files, fileReport)
+		changed = append(changed, file)
+	}
+	report.Status = repositoryStatus(report.Files)
+
+	level.Info(am.logger).Log("event", "distribute_repository.status", "repository", repository.GetName(), "status", report.Status)
+	if len(changed) == 0 {
+		return report, nil
+	}
+
+	result, err := am.CommitFiles(ctx, repository, changed, commit)
+	if err != nil {
+		return nil, err
+	}
+	if result.Unchanged {
+		for _, fileReport := range report.Files {
+			fileReport.Status = StatusUnchanged
+		}
+		report.Status = StatusUnchanged
+		level.Info(am.logger).Log("event", "distribute_repository.status", "repository", repository.GetName(), "status", report.Status)
+		return report, nil
+	}
+	report.PullRequest = result.PullRequest
+	report.Commit = result.SHA
+
+	if opts.VerifyRuns && result.SHA != "" {
+		report.Run, err = am.VerifyRun(ctx, repository.GetName(), file.Path, result.SHA, opts.RunTimeout, opts.RunPollInterval)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify workflow run: %w", err)
+		}
+	}
+
+	return report, nil
+}
+
+// workflowRepositoryFile returns the rendered workflow as a file to commit, or
+// the existing workflow it is injected into.
+func (am *ActionManager) workflowRepositoryFile(ctx context.Context, repository, ref string, workflowFile *WorkflowFile, opts DistributeOptions) (*RepositoryFile, error) {
+	if opts.InjectInto != "" {
+		file, err := am.InjectionFile(ctx, repository, ref, opts.InjectInto, opts.InjectJob, workflowFile)
+		if err != nil {
+			return nil, err
+		}
+
+		err = ValidateWorkflow(file.Content)
+		if err != nil {
+			return nil, fmt.Errorf("invalid workflow %s: %w", file.Path, err)
 		}
+		return file, nil
 	}
 
-	return len(success), len(failures), nil
+	existing, err := am.GetFile(ctx, repository, workflowFile.Path, ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file: %w", err)
+	}
+
+	file := &RepositoryFile{Path: workflowFile.Path, Content: workflowFile.Content}
+	if existing != nil {
+		file.SHA = existing.SHA
+		file.Previous = existing.Content
+	}
+	return file, nil
 }
 
-func (am *ActionManager) ListRepositories(ctx context.Context, private bool) ([]*github.Repository, error) {
-	filter := "all"
-	if private {
-		filter = "private"
+func (am *ActionManager) commitOptions(repository *github.Repository, opts DistributeOptions) (CommitOptions, error) {
+	commit := CommitOptions{
+		Message:        DefaultCommitMessage,
+		Branch:         opts.Branch,
+		Author:         opts.Author,
+		Committer:      opts.Committer,
+		PullRequest:    opts.PullRequest,
+		Signer:         opts.Signer,
+		RequireSigning: opts.RequireSigning,
 	}
 
-	opts := &github.RepositoryListByOrgOptions{
-		Type:        filter,
-		ListOptions: github.ListOptions{PerPage: 100},
+	if opts.CommitMessage != nil {
+		var err error
+		commit.Message, err = opts.CommitMessage.Render(CommitMessageVariables{
+			Organisation: am.organisation,
+			Repository:   repository.GetName(),
+			Version:      am.workflowTemplate.Version,
+		})
+		if err != nil {
+			return CommitOptions{}, err
+		}
+	}
+
+	return commit, nil
+}
+
+// DependabotFile returns the Dependabot config covering the given directories,
+// merged into the existing config on ref if there is one. It returns nil if the
+// existing config covers every directory already.
+func (am *ActionManager) DependabotFile(ctx context.Context, repository, ref string, directories []string, schedule string) (*RepositoryFile, error) {
+	existing, err := am.GetDependabotConfig(ctx, repository, ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file: %w", err)
 	}
 
-	var list []*github.Repository
-	for {
-		repositories, response, err := am.repositoriesService.ListByOrg(ctx, am.organisation, opts)
+	if existing == nil {
+		content, err := NewDependabotConfig(directories, schedule).Marshal()
 		if err != nil {
 			return nil, err
 		}
-		list = append(list, repositories...)
-		if response.NextPage == 0 {
-			break
+		return &RepositoryFile{Path: DependabotPath, Content: content}, nil
+	}
+
+	content, added, err := MergeDependabotConfig(existing.Content, directories, schedule)
+	if err != nil {
+		return nil, err
+	}
+	if len(added) == 0 {
+		level.Info(am.logger).Log("event", "dependabot.covered", "repository", repository)
+		return nil, nil
+	}
+
+	level.Info(am.logger).Log("event", "dependabot.merge", "repository", repository, "directories", strings.Join(added, ","))
+	return &RepositoryFile{Path: existing.Path, Content: content, SHA: existing.SHA, Previous: existing.Content}, nil
+}
+
+// CommitFiles commits a single file directly to a branch through the contents
+// API, and otherwise creates one commit for all the files through the Git Data
+// API, which is also needed to sign commits.
+func (am *ActionManager) CommitFiles(ctx context.Context, repository *github.Repository, files []*RepositoryFile, commit CommitOptions) (*CommitResult, error) {
+	if len(files) == 1 && !commit.PullRequest && commit.Signer == nil {
+		file := files[0]
+		if file.SHA == "" {
+			sha, err := am.CreateFile(ctx, repository.GetName(), file.Path, file.Content, commit)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create file: %w", err)
+			}
+			return &CommitResult{SHA: sha}, nil
+		}
+
+		sha, err := am.UpdateFile(ctx, repository.GetName(), file.Path, file.Content, file.SHA, commit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update file: %w", err)
+		}
+		return &CommitResult{SHA: sha}, nil
+	}
+
+	request := &CommitRequest{
+		Repository:     repository.GetName(),
+		BaseBranch:     repository.GetDefaultBranch(),
+		Branch:         commit.Branch,
+		Message:        commit.Message,
+		Author:         commit.Author,
+		Committer:      commit.Committer,
+		Files:          files,
+		PullRequest:    commit.PullRequest,
+		Signer:         commit.Signer,
+		RequireSigning: commit.RequireSigning,
+	}
+	if request.Branch == "" {
+		request.Branch = repository.GetDefaultBranch()
+		if commit.PullRequest {
+			request.Branch = DefaultPullRequestBranch
 		}
-		opts.Page = response.NextPage
 	}
 
-	return list, nil
+	result, err := am.Commit(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit files: %w", err)
+	}
+
+	return result, nil
 }
 
-func (am *ActionManager) CreateFile(ctx context.Context, repository, path string, content []byte) error {
+func (am *ActionManager) RenderWorkflow(repository *github.Repository, dockerfiles []string) (*WorkflowFile, error) {
+	variables := am.workflowTemplate.Variables(am.organisation, repository, dockerfiles)
 	if am.dryRun {
-		level.Info(am.logger).Log("event", "create_file.dry_run", "repository", repository)
-		return nil
+		level.Info(am.logger).Log("event", "render_workflow.dry_run", "repository", repository.GetName(), "overrides", strings.Join(variables.Overrides, ","))
 	}
 
-	opts := &github.RepositoryContentFileOptions{
-		Message: github.String("GitHub Actions workflow for Mobydick"),
-		Content: content,
+	workflowFile, err := am.workflowTemplate.Render(variables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render workflow: %w", err)
 	}
 
-	_, _, err := am.repositoriesService.CreateFile(ctx, am.organisation, repository, path, opts)
+	err = ValidateWorkflow(workflowFile.Content)
 	if err != nil {
-		level.Info(am.logger).Log("event", "create_file.failure", "repository", repository, "error", err)
-		return err
+		return nil, err
 	}
 
-	level.Info(am.logger).Log("event", "create_file.success", "repository", repository)
-	return nil
+	return workflowFile, nil
 }
 
-type createFileJob struct {
+// RepositoryFile is a file to commit to a repository. For files that exist on
+// the branch already, SHA and Previous hold the blob SHA and content read from it.
+type RepositoryFile struct {
+	Path     string
+	Content  []byte
+	SHA      string
+	Previous []byte
+}
+
+// GetFile returns the file at path on ref, or the default branch of the
+// repository if ref is empty, or nil if it does not exist.
+func (am *ActionManager) GetFile(ctx context.Context, repository, path, ref string) (*RepositoryFile, error) {
+	var opts *github.RepositoryContentGetOptions
+	if ref != "" {
+		opts = &github.RepositoryContentGetOptions{Ref: ref}
+	}
+
+	file, _, response, err := am.repositoriesService.GetContents(ctx, am.organisation, repository, path, opts)
+	if err != nil {
+		if response != nil && response.Response != nil && response.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if file == nil {
+		return nil, fmt.Errorf("%s is not a file", path)
+	}
+
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, err
+	}
+
+	return &RepositoryFile{
+		Path:    path,
+		Content: []byte(content),
+		SHA:     file.GetSHA(),
+	}, nil
+}
+
+func (am *ActionManager) CreateFile(ctx context.Context, repository, path string, content []byte, commit CommitOptions) (string, error) {
+	if am.dryRun {
+		level.Info(am.logger).Log("event", "create_file.dry_run", "repository", repository, "path", path)
+		return "", nil
+	}
+
+	opts := commit.fileOptions(content)
+
+	response, _, err := am.repositoriesService.CreateFile(ctx, am.organisation, repository, path, opts)
+	if err != nil {
+		level.Info(am.logger).Log("event", "create_file.failure", "repository", repository, "path", path, "error", err)
+		return "", err
+	}
+
+	level.Info(am.logger).Log("event", "create_file.success", "repository", repository, "path", path)
+	return response.GetSHA(), nil
+}
+
+func (am *ActionManager) UpdateFile(ctx context.Context, repository, path string, content []byte, sha string, commit CommitOptions) (string, error) {
+	if am.dryRun {
+		level.Info(am.logger).Log("event", "update_file.dry_run", "repository", repository, "path", path)
+		return "", nil
+	}
+
+	opts := commit.fileOptions(content)
+	opts.SHA = github.String(sha)
+
+	response, _, err := am.repositoriesService.UpdateFile(ctx, am.organisation, repository, path, opts)
+	if err != nil {
+		level.Info(am.logger).Log("event", "update_file.failure", "repository", repository, "path", path, "error", err)
+		return "", err
+	}
+
+	level.Info(am.logger).Log("event", "update_file.success", "repository", repository, "path", path)
+	return response.GetSHA(), nil
+}
+
+type distributeJob struct {
 	handler    *ActionManager
-	repository string
-	path       string
-	content    []byte
+	repository *github.Repository
+	opts       DistributeOptions
+	report     *RepositoryReport
 }
 
-func (job *createFileJob) Process(ctx context.Context) error {
-	err := job.handler.CreateFile(ctx, job.repository, job.path, job.content)
+func (job *distributeJob) Repository() *github.Repository {
+	return job.repository
+}
+
+func (job *distributeJob) Process(ctx context.Context) error {
+	report, err := job.handler.DistributeRepository(ctx, job.repository, job.opts)
 	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
+		return err
+	}
+	job.report = report
+
+	// The commit was made, but the circuit breaker should still count a workflow run that didn't pass
+	if report.Run != nil && report.Run.Status != RunPassed {
+		return &runError{repository: job.repository.GetName(), run: report.Run}
 	}
 	return nil
 }
+
+type runError struct {
+	repository string
+	run        *RunReport
+}
+
+func (e *runError) Error() string {
+	return fmt.Sprintf("workflow run in %s %s: %s", e.repository, e.run.Status, e.run.URL)
+}
@@ -3,11 +3,14 @@ package action_test
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/go-kit/kit/log"
 	"github.com/google/go-github/v29/github"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/jace-ys/mobydick-action/bin/pkg/action"
 	"github.com/jace-ys/mobydick-action/bin/pkg/action/actionfakes"
@@ -19,17 +22,19 @@ func TestActionManager(t *testing.T) {
 	defer cancel()
 
 	logger := log.NewNopLogger()
+	pullRequestsService := new(actionfakes.FakePullRequestsService)
 
 	t.Run("ListRepositories", func(t *testing.T) {
 		workerPool := &worker.WorkerPool{}
-		workflowFile := &action.WorkflowFile{}
+		workflowTemplate := fakeWorkflowTemplate(t)
+		gitService := new(actionfakes.FakeGitService)
 
 		t.Run("Error", func(t *testing.T) {
 			repositoriesService := new(actionfakes.FakeRepositoriesService)
 			repositoriesService.ListByOrgReturnsOnCall(0, fakeRepositories(0), &github.Response{NextPage: 0}, fmt.Errorf("could not list repositories"))
 
-			actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowFile, workerPool, repositoriesService)
-			repositories, err := actionManager.ListRepositories(ctx, true)
+			actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+			repositories, err := actionManager.ListRepositories(ctx, action.RepositoryFilter{Private: true})
 
 			assert.Equal(t, 1, repositoriesService.ListByOrgCallCount())
 			assert.Error(t, err)
@@ -40,8 +45,8 @@ func TestActionManager(t *testing.T) {
 			repositoriesService := new(actionfakes.FakeRepositoriesService)
 			repositoriesService.ListByOrgReturnsOnCall(0, fakeRepositories(2), &github.Response{NextPage: 0}, nil)
 
-			actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowFile, workerPool, repositoriesService)
-			repositories, err := actionManager.ListRepositories(ctx, true)
+			actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+			repositories, err := actionManager.ListRepositories(ctx, action.RepositoryFilter{Private: true})
 
 			assert.Equal(t, 1, repositoriesService.ListByOrgCallCount())
 			assert.NoError(t, err)
@@ -53,8 +58,8 @@ func TestActionManager(t *testing.T) {
 			repositoriesService.ListByOrgReturnsOnCall(0, fakeRepositories(2), &github.Response{NextPage: 1}, nil)
 			repositoriesService.ListByOrgReturnsOnCall(1, fakeRepositories(2), &github.Response{NextPage: 0}, nil)
 
-			actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowFile, workerPool, repositoriesService)
-			repositories, err := actionManager.ListRepositories(ctx, true)
+			actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+			repositories, err := actionManager.ListRepositories(ctx, action.RepositoryFilter{Private: true})
 
 			assert.Equal(t, 2, repositoriesService.ListByOrgCallCount())
 			assert.NoError(t, err)
@@ -63,10 +68,12 @@ func TestActionManager(t *testing.T) {
 	})
 
 	t.Run("CreateFile", func(t *testing.T) {
+		workflowTemplate := fakeWorkflowTemplate(t)
 		workflowFile := &action.WorkflowFile{
 			Path:    "path/to/workflow.yaml",
 			Content: []byte("content"),
 		}
+		gitService := new(actionfakes.FakeGitService)
 
 		t.Run("Error", func(t *testing.T) {
 			repositoriesService := new(actionfakes.FakeRepositoriesService)
@@ -74,8 +81,8 @@ func TestActionManager(t *testing.T) {
 
 			workerPool := worker.NewWorkerPool(1)
 
-			actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowFile, workerPool, repositoriesService)
-			err := actionManager.CreateFile(ctx, "repository", workflowFile.Path, workflowFile.Content)
+			actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+			_, err := actionManager.CreateFile(ctx, "repository", workflowFile.Path, workflowFile.Content, action.CommitOptions{Message: "message"})
 
 			assert.Equal(t, 1, repositoriesService.CreateFileCallCount())
 			assert.Error(t, err)
@@ -86,8 +93,8 @@ func TestActionManager(t *testing.T) {
 
 			workerPool := worker.NewWorkerPool(1)
 
-			actionManager := action.NewActionManager(ctx, logger, "organisation", true, workflowFile, workerPool, repositoriesService)
-			err := actionManager.CreateFile(ctx, "repository", workflowFile.Path, workflowFile.Content)
+			actionManager := action.NewActionManager(ctx, logger, "organisation", true, workflowTemplate, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+			_, err := actionManager.CreateFile(ctx, "repository", workflowFile.Path, workflowFile.Content, action.CommitOptions{Message: "message"})
 
 			assert.Equal(t, 0, repositoriesService.CreateFileCallCount())
 			assert.NoError(t, err)
@@ -99,61 +106,445 @@ func TestActionManager(t *testing.T) {
 
 			workerPool := worker.NewWorkerPool(1)
 
-			actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowFile, workerPool, repositoriesService)
-			err := actionManager.CreateFile(ctx, "repository", workflowFile.Path, workflowFile.Content)
+			actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+			_, err := actionManager.CreateFile(ctx, "repository", workflowFile.Path, workflowFile.Content, action.CommitOptions{Message: "message"})
 
 			assert.Equal(t, 1, repositoriesService.CreateFileCallCount())
 			assert.NoError(t, err)
 		})
 	})
 
+	t.Run("ListDockerfiles", func(t *testing.T) {
+		workerPool := worker.NewWorkerPool(1)
+		workflowTemplate := fakeWorkflowTemplate(t)
+		repositoriesService := new(actionfakes.FakeRepositoriesService)
+
+		t.Run("Error", func(t *testing.T) {
+			gitService := new(actionfakes.FakeGitService)
+			gitService.GetTreeReturnsOnCall(0, nil, &github.Response{}, fmt.Errorf("could not get tree"))
+
+			actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+			dockerfiles, err := actionManager.ListDockerfiles(ctx, fakeRepositories(1)[0])
+
+			assert.Equal(t, 1, gitService.GetTreeCallCount())
+			assert.Error(t, err)
+			assert.Equal(t, 0, len(dockerfiles))
+		})
+
+		t.Run("Success", func(t *testing.T) {
+			gitService := new(actionfakes.FakeGitService)
+			gitService.GetTreeReturnsOnCall(0, fakeTree("Dockerfile", "docker/api/Dockerfile", "docker/api/Dockerfile.dev", "README.md"), &github.Response{}, nil)
+
+			actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+			dockerfiles, err := actionManager.ListDockerfiles(ctx, fakeRepositories(1)[0])
+
+			assert.Equal(t, 1, gitService.GetTreeCallCount())
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"Dockerfile", "docker/api/Dockerfile", "docker/api/Dockerfile.dev"}, dockerfiles)
+		})
+	})
+
+	t.Run("RenderWorkflow", func(t *testing.T) {
+		workerPool := worker.NewWorkerPool(1)
+		repositoriesService := new(actionfakes.FakeRepositoriesService)
+		gitService := new(actionfakes.FakeGitService)
+
+		t.Run("Error", func(t *testing.T) {
+			workflowTemplate := newWorkflowTemplate(t, "on: push\njobs: {}\n")
+
+			actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+			workflowFile, err := actionManager.RenderWorkflow(fakeRepositories(1)[0], nil)
+
+			assert.Error(t, err)
+			assert.Nil(t, workflowFile)
+		})
+
+		t.Run("Success", func(t *testing.T) {
+			workflowTemplate := fakeWorkflowTemplate(t)
+
+			actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+			workflowFile, err := actionManager.RenderWorkflow(fakeRepositories(1)[0], []string{"Dockerfile", "docker/api/Dockerfile"})
+
+			assert.NoError(t, err)
+			assert.Equal(t, "path/to/workflow.yaml", workflowFile.Path)
+			assert.Contains(t, string(workflowFile.Content), "uses: organisation/repository@v1.0.0")
+			assert.Contains(t, string(workflowFile.Content), `directories: "[. docker/api]"`)
+		})
+	})
+
 	t.Run("DistributeCommand", func(t *testing.T) {
-		workflowFile := &action.WorkflowFile{
-			Path:    "path/to/workflow.yaml",
-			Content: []byte("content"),
-		}
+		workflowTemplate := fakeWorkflowTemplate(t)
+		gitService := new(actionfakes.FakeGitService)
+		gitService.GetTreeReturns(fakeTree(), &github.Response{}, nil)
+
+		t.Run("InvalidTemplate", func(t *testing.T) {
+			repositoriesService := new(actionfakes.FakeRepositoriesService)
+			workflowTemplate := newWorkflowTemplate(t, "on: push\njobs: {}\n")
+
+			workerPool := worker.NewWorkerPool(1)
+
+			actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+			_, err := actionManager.Distribute(ctx, action.DistributeOptions{Filter: action.RepositoryFilter{Private: true}})
+
+			assert.Equal(t, 0, repositoriesService.ListByOrgCallCount())
+			assert.Error(t, err)
+		})
 
 		t.Run("Failure", func(t *testing.T) {
 			repositoriesService := new(actionfakes.FakeRepositoriesService)
 			repositoriesService.ListByOrgReturnsOnCall(0, fakeRepositories(1), &github.Response{NextPage: 0}, nil)
+			repositoriesService.GetContentsReturns(nil, nil, fakeResponse(http.StatusNotFound), fmt.Errorf("not found"))
 			repositoriesService.CreateFileReturnsOnCall(0, &github.RepositoryContentResponse{}, &github.Response{}, fmt.Errorf("failed to create file"))
 
 			workerPool := worker.NewWorkerPool(1)
 
-			actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowFile, workerPool, repositoriesService)
-			success, failures, err := actionManager.Distribute(ctx, true)
+			actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+			report, err := actionManager.Distribute(ctx, action.DistributeOptions{Filter: action.RepositoryFilter{Private: true}})
 
 			assert.Equal(t, 1, repositoriesService.ListByOrgCallCount())
 			assert.Equal(t, 1, repositoriesService.CreateFileCallCount())
 			assert.NoError(t, err)
-			assert.Equal(t, 0, success)
-			assert.Equal(t, 1, failures)
+			assert.Equal(t, 1, report.Count(action.StatusFailed))
+		})
+
+		t.Run("CircuitBreaker", func(t *testing.T) {
+			repositoriesService := new(actionfakes.FakeRepositoriesService)
+			repositoriesService.ListByOrgReturnsOnCall(0, fakeRepositories(5), &github.Response{NextPage: 0}, nil)
+			repositoriesService.GetContentsReturns(nil, nil, fakeResponse(http.StatusNotFound), fmt.Errorf("not found"))
+			repositoriesService.CreateFileReturns(nil, fakeResponse(http.StatusForbidden), fmt.Errorf("resource not accessible by integration"))
+
+			workerPool := worker.NewWorkerPool(1, worker.WithCircuitBreaker(&worker.CircuitBreaker{MaxConsecutiveFailures: 2}))
+
+			actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+			report, err := actionManager.Distribute(ctx, action.DistributeOptions{Filter: action.RepositoryFilter{Private: true}})
+
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "resource not accessible by integration")
+			assert.Equal(t, 2, repositoriesService.CreateFileCallCount())
+			assert.Equal(t, 2, report.Count(action.StatusFailed))
+			assert.Equal(t, 3, report.Count(action.StatusAborted))
+		})
+
+		t.Run("WithDependabot", func(t *testing.T) {
+			repositoriesService := new(actionfakes.FakeRepositoriesService)
+			repositoriesService.ListByOrgReturnsOnCall(0, fakeRepositories(1), &github.Response{NextPage: 0}, nil)
+			repositoriesService.GetContentsReturns(nil, nil, fakeResponse(http.StatusNotFound), fmt.Errorf("not found"))
+			repositoriesService.CreateFileReturns(&github.RepositoryContentResponse{}, &github.Response{}, nil)
+
+			gitService := fakeGitService()
+			gitService.GetTreeReturns(fakeTree("Dockerfile"), &github.Response{}, nil)
+
+			workerPool := worker.NewWorkerPool(1)
+
+			actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+			report, err := actionManager.Distribute(ctx, action.DistributeOptions{DependabotSchedule: "daily"})
+
+			assert.Equal(t, 0, repositoriesService.CreateFileCallCount())
+			assert.Equal(t, 2, gitService.CreateBlobCallCount())
+			_, _, _, blob := gitService.CreateBlobArgsForCall(1)
+			assert.Contains(t, blob.GetContent(), "package-ecosystem: docker")
+			_, _, _, _, entries := gitService.CreateTreeArgsForCall(0)
+			assert.Equal(t, action.DependabotPath, entries[1].GetPath())
+			assert.Equal(t, 1, gitService.CreateCommitCallCount())
+			assert.Equal(t, 1, gitService.UpdateRefCallCount())
+			assert.NoError(t, err)
+			assert.Equal(t, 0, report.Count(action.StatusFailed))
+		})
+
+		t.Run("WithExistingDependabot", func(t *testing.T) {
+			repositoriesService := new(actionfakes.FakeRepositoriesService)
+			repositoriesService.ListByOrgReturnsOnCall(0, fakeRepositories(1), &github.Response{NextPage: 0}, nil)
+			repositoriesService.GetContentsStub = func(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error) {
+				if path == action.DependabotPath {
+					return fakeContent("version: 2\nupdates:\n  - package-ecosystem: gomod\n    directory: /\n    schedule:\n      interval: daily\n"), nil, &github.Response{}, nil
+				}
+				return nil, nil, fakeResponse(http.StatusNotFound), fmt.Errorf("not found")
+			}
+			repositoriesService.CreateFileReturns(&github.RepositoryContentResponse{}, &github.Response{}, nil)
+			repositoriesService.UpdateFileReturns(&github.RepositoryContentResponse{}, &github.Response{}, nil)
+
+			gitService := fakeGitService()
+			gitService.GetTreeReturns(fakeTree("Dockerfile"), &github.Response{}, nil)
+
+			workerPool := worker.NewWorkerPool(1)
+
+			actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+			report, err := actionManager.Distribute(ctx, action.DistributeOptions{DependabotSchedule: "daily"})
+
+			assert.Equal(t, 2, gitService.CreateBlobCallCount())
+			_, _, _, blob := gitService.CreateBlobArgsForCall(1)
+			assert.Contains(t, blob.GetContent(), "package-ecosystem: gomod")
+			assert.Contains(t, blob.GetContent(), "package-ecosystem: docker")
+			assert.Equal(t, 1, gitService.UpdateRefCallCount())
+			assert.NoError(t, err)
+			assert.Equal(t, 0, report.Count(action.StatusFailed))
+		})
+
+		t.Run("WithExistingDependabotYaml", func(t *testing.T) {
+			repositoriesService := new(actionfakes.FakeRepositoriesService)
+			repositoriesService.ListByOrgReturnsOnCall(0, fakeRepositories(1), &github.Response{NextPage: 0}, nil)
+			repositoriesService.GetContentsStub = func(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error) {
+				if path == ".github/dependabot.yaml" {
+					return fakeContent("version: 2\nupdates:\n  - package-ecosystem: gomod\n    directory: /\n    schedule:\n      interval: daily\n"), nil, &github.Response{}, nil
+				}
+				return nil, nil, fakeResponse(http.StatusNotFound), fmt.Errorf("not found")
+			}
+
+			gitService := fakeGitService()
+			gitService.GetTreeReturns(fakeTree("Dockerfile"), &github.Response{}, nil)
+
+			workerPool := worker.NewWorkerPool(1)
+
+			actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+			report, err := actionManager.Distribute(ctx, action.DistributeOptions{DependabotSchedule: "daily"})
+
+			require.Equal(t, 1, gitService.CreateTreeCallCount())
+			_, _, _, _, entries := gitService.CreateTreeArgsForCall(0)
+			require.Len(t, entries, 2)
+			assert.Equal(t, ".github/dependabot.yaml", entries[1].GetPath())
+			_, _, _, blob := gitService.CreateBlobArgsForCall(1)
+			assert.Contains(t, blob.GetContent(), "package-ecosystem: gomod")
+			assert.Contains(t, blob.GetContent(), "package-ecosystem: docker")
+			assert.NoError(t, err)
+			assert.Equal(t, 0, report.Count(action.StatusFailed))
+		})
+
+		t.Run("WithCommitOptions", func(t *testing.T) {
+			repositoriesService := new(actionfakes.FakeRepositoriesService)
+			repositoriesService.ListByOrgReturnsOnCall(0, fakeRepositories(1), &github.Response{NextPage: 0}, nil)
+			repositoriesService.GetContentsReturns(nil, nil, fakeResponse(http.StatusNotFound), fmt.Errorf("not found"))
+			repositoriesService.CreateFileReturnsOnCall(0, &github.RepositoryContentResponse{}, &github.Response{}, nil)
+
+			message, err := action.NewCommitMessage("ci: add mobydick {{ .Version }} to {{ .Repository }}")
+			require.NoError(t, err)
+			author := &github.CommitAuthor{Name: github.String("bot"), Email: github.String("bot@example.com")}
+
+			workerPool := worker.NewWorkerPool(1)
+
+			actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+			report, err := actionManager.Distribute(ctx, action.DistributeOptions{
+				CommitMessage: message,
+				Branch:        "develop",
+				Author:        author,
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, 1, report.Count(action.StatusCreated))
+			_, _, _, _, opts := repositoriesService.CreateFileArgsForCall(0)
+			assert.Equal(t, "ci: add mobydick v1.0.0 to repository", opts.GetMessage())
+			assert.Equal(t, "develop", opts.GetBranch())
+			assert.Equal(t, author, opts.Author)
+			assert.Nil(t, opts.Committer)
+		})
+
+		rendered := "on: push\njobs:\n  mobydick:\n    runs-on: ubuntu-latest\n    steps:\n      - uses: organisation/repository@v1.0.0\n        with:\n          directories: \"[]\"\n"
+
+		t.Run("Unchanged", func(t *testing.T) {
+			repositoriesService := new(actionfakes.FakeRepositoriesService)
+			repositoriesService.ListByOrgReturnsOnCall(0, fakeRepositories(1), &github.Response{NextPage: 0}, nil)
+			repositoriesService.GetContentsReturns(fakeContent(rendered), nil, &github.Response{}, nil)
+
+			workerPool := worker.NewWorkerPool(1)
+
+			actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+			report, err := actionManager.Distribute(ctx, action.DistributeOptions{})
+
+			assert.NoError(t, err)
+			assert.Equal(t, 1, report.Count(action.StatusUnchanged))
+			assert.Equal(t, 0, repositoriesService.CreateFileCallCount())
+			assert.Equal(t, 0, repositoriesService.UpdateFileCallCount())
+		})
+
+		t.Run("Update", func(t *testing.T) {
+			repositoriesService := new(actionfakes.FakeRepositoriesService)
+			repositoriesService.ListByOrgReturnsOnCall(0, fakeRepositories(1), &github.Response{NextPage: 0}, nil)
+			repositoriesService.GetContentsReturns(fakeContent("on: push\n"), nil, &github.Response{}, nil)
+			repositoriesService.UpdateFileReturns(&github.RepositoryContentResponse{}, &github.Response{}, nil)
+
+			workerPool := worker.NewWorkerPool(1)
+
+			actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+			report, err := actionManager.Distribute(ctx, action.DistributeOptions{})
+
+			assert.NoError(t, err)
+			assert.Equal(t, 1, report.Count(action.StatusUpdated))
+			assert.Equal(t, 0, repositoriesService.CreateFileCallCount())
+			assert.Equal(t, 1, repositoriesService.UpdateFileCallCount())
+			_, _, _, _, opts := repositoriesService.UpdateFileArgsForCall(0)
+			assert.Equal(t, "sha", opts.GetSHA())
+		})
+
+		t.Run("PullRequestUnchanged", func(t *testing.T) {
+			repositoriesService := new(actionfakes.FakeRepositoriesService)
+			repositoriesService.ListByOrgReturnsOnCall(0, fakeRepositories(1), &github.Response{NextPage: 0}, nil)
+			repositoriesService.GetContentsReturns(fakeContent("on: push\n"), nil, &github.Response{}, nil)
+
+			gitService := fakeGitService()
+			gitService.GetTreeReturns(fakeTree(), &github.Response{}, nil)
+			gitService.CreateTreeReturns(&github.Tree{SHA: github.String("tree")}, &github.Response{}, nil)
+			pullRequestsService := new(actionfakes.FakePullRequestsService)
+
+			workerPool := worker.NewWorkerPool(1)
+
+			actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+			report, err := actionManager.Distribute(ctx, action.DistributeOptions{PullRequest: true})
+
+			assert.NoError(t, err)
+			assert.Equal(t, 1, report.Count(action.StatusUnchanged))
+			assert.Empty(t, report.Repositories[0].Commit)
+			assert.Equal(t, 0, gitService.CreateCommitCallCount())
+			assert.Equal(t, 0, gitService.UpdateRefCallCount())
+			assert.Equal(t, 0, pullRequestsService.CreateCallCount())
+		})
+
+		t.Run("ActionUsedElsewhere", func(t *testing.T) {
+			newRepositoriesService := func() *actionfakes.FakeRepositoriesService {
+				repositoriesService := new(actionfakes.FakeRepositoriesService)
+				repositoriesService.ListByOrgReturns(fakeRepositories(1), &github.Response{NextPage: 0}, nil)
+				repositoriesService.GetContentsStub = func(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error) {
+					switch path {
+					case action.WorkflowsDirectory:
+						return nil, []*github.RepositoryContent{
+							{Name: github.String("ci.yml"), Path: github.String(".github/workflows/ci.yml"), Type: github.String("file")},
+							{Name: github.String("workflow.yaml"), Path: github.String("path/to/workflow.yaml"), Type: github.String("file")},
+						}, &github.Response{}, nil
+					case ".github/workflows/ci.yml":
+						return fakeContent("on: push\njobs:\n  build:\n    steps:\n      - run: make\n      - uses: jace-ys/mobydick-action@v0.1.0\n"), nil, &github.Response{}, nil
+					default:
+						return nil, nil, fakeResponse(http.StatusNotFound), fmt.Errorf("not found")
+					}
+				}
+				repositoriesService.CreateFileReturns(&github.RepositoryContentResponse{}, &github.Response{}, nil)
+				return repositoriesService
+			}
+
+			t.Run("Skip", func(t *testing.T) {
+				repositoriesService := newRepositoriesService()
+				workerPool := worker.NewWorkerPool(1)
+
+				actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+				report, err := actionManager.Distribute(ctx, action.DistributeOptions{})
+
+				require.NoError(t, err)
+				assert.Equal(t, 1, report.Count(action.StatusSkipped))
+				assert.Equal(t, []string{".github/workflows/ci.yml:jobs.build.steps[1]"}, report.Repositories[0].ActionUses)
+				assert.Equal(t, 0, repositoriesService.CreateFileCallCount())
+			})
+
+			t.Run("AllowDuplicates", func(t *testing.T) {
+				repositoriesService := newRepositoriesService()
+				workerPool := worker.NewWorkerPool(1)
+
+				actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+				report, err := actionManager.Distribute(ctx, action.DistributeOptions{AllowDuplicates: true})
+
+				require.NoError(t, err)
+				assert.Equal(t, 1, report.Count(action.StatusCreated))
+				assert.Equal(t, []string{".github/workflows/ci.yml:jobs.build.steps[1]"}, report.Repositories[0].ActionUses)
+				assert.Equal(t, 1, repositoriesService.CreateFileCallCount())
+			})
+		})
+
+		t.Run("DryRun", func(t *testing.T) {
+			repositoriesService := new(actionfakes.FakeRepositoriesService)
+			repositoriesService.ListByOrgReturnsOnCall(0, []*github.Repository{
+				{Name: github.String("existing")},
+				{Name: github.String("new")},
+			}, &github.Response{NextPage: 0}, nil)
+			repositoriesService.GetContentsStub = func(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error) {
+				if repo == "existing" {
+					return fakeContent(strings.Replace(rendered, "organisation/repository@v1.0.0", "organisation/existing@v0.9.0", 1)), nil, &github.Response{}, nil
+				}
+				return nil, nil, fakeResponse(http.StatusNotFound), fmt.Errorf("not found")
+			}
+
+			workerPool := worker.NewWorkerPool(1)
+
+			actionManager := action.NewActionManager(ctx, logger, "organisation", true, workflowTemplate, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+			report, err := actionManager.Distribute(ctx, action.DistributeOptions{})
+
+			require.NoError(t, err)
+			assert.True(t, report.DryRun)
+			assert.Equal(t, 0, repositoriesService.CreateFileCallCount())
+			assert.Equal(t, 0, repositoriesService.UpdateFileCallCount())
+			require.Equal(t, 2, len(report.Repositories))
+
+			existing := report.Repositories[0]
+			assert.Equal(t, "existing", existing.Repository)
+			assert.Equal(t, action.StatusWouldUpdate, existing.Status)
+			assert.Contains(t, existing.Files[0].Diff, "--- a/path/to/workflow.yaml\n+++ b/path/to/workflow.yaml\n")
+			assert.Contains(t, existing.Files[0].Diff, "\n-      - uses: organisation/existing@v0.9.0\n+      - uses: organisation/existing@v1.0.0\n")
+
+			created := report.Repositories[1]
+			assert.Equal(t, "new", created.Repository)
+			assert.Equal(t, action.StatusWouldCreate, created.Status)
+			assert.Contains(t, created.Files[0].Diff, "--- /dev/null\n+++ b/path/to/workflow.yaml\n")
+			assert.Contains(t, created.Files[0].Diff, "\n+on: push\n")
 		})
 
 		t.Run("Success", func(t *testing.T) {
 			repositoriesService := new(actionfakes.FakeRepositoriesService)
 			repositoriesService.ListByOrgReturnsOnCall(0, fakeRepositories(1), &github.Response{NextPage: 0}, nil)
+			repositoriesService.GetContentsReturns(nil, nil, fakeResponse(http.StatusNotFound), fmt.Errorf("not found"))
 			repositoriesService.CreateFileReturnsOnCall(0, &github.RepositoryContentResponse{}, &github.Response{}, nil)
 
 			workerPool := worker.NewWorkerPool(1)
 
-			actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowFile, workerPool, repositoriesService)
-			success, failures, err := actionManager.Distribute(ctx, true)
+			actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+			report, err := actionManager.Distribute(ctx, action.DistributeOptions{Filter: action.RepositoryFilter{Private: true}})
 
 			assert.Equal(t, 1, repositoriesService.ListByOrgCallCount())
 			assert.Equal(t, 1, repositoriesService.CreateFileCallCount())
 			assert.NoError(t, err)
-			assert.Equal(t, 1, success)
-			assert.Equal(t, 0, failures)
+			assert.Equal(t, 0, report.Count(action.StatusFailed))
 		})
 	})
 }
 
+func fakeWorkflowTemplate(t *testing.T) *action.WorkflowTemplate {
+	return newWorkflowTemplate(t, `on: push
+jobs:
+  mobydick:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: {{ .Organisation }}/{{ .Repository.Name }}@{{ .Version }}
+        with:
+          directories: "{{ .DockerfileDirectories }}"
+`)
+}
+
+func newWorkflowTemplate(t *testing.T, text string) *action.WorkflowTemplate {
+	workflowTemplate, err := action.NewWorkflowTemplate("path/to/workflow.yaml", []byte(text), "v1.0.0", action.Delimiters{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return workflowTemplate
+}
+
+func fakeTree(paths ...string) *github.Tree {
+	tree := &github.Tree{}
+	for _, path := range paths {
+		tree.Entries = append(tree.Entries, github.TreeEntry{Path: github.String(path), Type: github.String("blob")})
+	}
+	return tree
+}
+
+func fakeContent(content string) *github.RepositoryContent {
+	return &github.RepositoryContent{
+		Content: github.String(content),
+		SHA:     github.String("sha"),
+	}
+}
+
+func fakeResponse(status int) *github.Response {
+	return &github.Response{Response: &http.Response{StatusCode: status}}
+}
+
 func fakeRepositories(num int) []*github.Repository {
 	var repositories []*github.Repository
 	name := "repository"
 	for i := 0; i < num; i++ {
-		repositories = append(repositories, &github.Repository{Name: &name})
+		repositories = append(repositories, &github.Repository{Name: &name, Private: github.Bool(true)})
 	}
 	return repositories
 }
@@ -0,0 +1,53 @@
+package action
+
+import (
+	"fmt"
+
+	"github.com/google/go-github/v29/github"
+
+	"github.com/jace-ys/mobydick-action/bin/pkg/worker"
+)
+
+const (
+	OrderListed = "listed"
+	OrderName   = "name"
+	OrderPushed = "pushed"
+	OrderStars  = "stars"
+)
+
+var Orders = []string{OrderListed, OrderName, OrderPushed, OrderStars}
+
+// repositoryJob is a job working on a single repository.
+type repositoryJob interface {
+	worker.Job
+	Repository() *github.Repository
+}
+
+// JobOrder returns the function to order jobs for repositories by with
+// worker.WithOrder: by name, most recently pushed first, or most starred last.
+// Jobs are left in the order repositories are listed in for OrderListed.
+func JobOrder(order string) (func(a, b worker.Job) bool, error) {
+	var less func(a, b *github.Repository) bool
+	switch order {
+	case OrderListed:
+		return nil, nil
+	case OrderName:
+		less = func(a, b *github.Repository) bool {
+			return a.GetName() < b.GetName()
+		}
+	case OrderPushed:
+		less = func(a, b *github.Repository) bool {
+			return a.GetPushedAt().After(b.GetPushedAt().Time)
+		}
+	case OrderStars:
+		less = func(a, b *github.Repository) bool {
+			return a.GetStargazersCount() < b.GetStargazersCount()
+		}
+	default:
+		return nil, fmt.Errorf("unknown order %q", order)
+	}
+
+	return func(a, b worker.Job) bool {
+		return less(a.(repositoryJob).Repository(), b.(repositoryJob).Repository())
+	}, nil
+}
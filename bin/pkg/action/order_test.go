@@ -0,0 +1,84 @@
+package action_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/go-github/v29/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jace-ys/mobydick-action/bin/pkg/action"
+	"github.com/jace-ys/mobydick-action/bin/pkg/action/actionfakes"
+	"github.com/jace-ys/mobydick-action/bin/pkg/worker"
+)
+
+func TestJobOrder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logger := log.NewNopLogger()
+	pullRequestsService := new(actionfakes.FakePullRequestsService)
+	workflowTemplate := fakeWorkflowTemplate(t)
+
+	pushed := func(days int) *github.Timestamp {
+		return &github.Timestamp{Time: time.Date(2020, 4, days, 0, 0, 0, 0, time.UTC)}
+	}
+
+	repositoriesService := new(actionfakes.FakeRepositoriesService)
+	repositoriesService.ListByOrgReturns([]*github.Repository{
+		{Name: github.String("b"), PushedAt: pushed(1), StargazersCount: github.Int(50)},
+		{Name: github.String("c"), PushedAt: pushed(3), StargazersCount: github.Int(0)},
+		{Name: github.String("a"), PushedAt: pushed(2), StargazersCount: github.Int(10)},
+	}, &github.Response{}, nil)
+	repositoriesService.GetContentsReturns(nil, nil, fakeResponse(http.StatusNotFound), fmt.Errorf("not found"))
+
+	testCases := []struct {
+		order    string
+		expected []string
+	}{
+		{order: action.OrderListed, expected: []string{"b", "c", "a"}},
+		{order: action.OrderName, expected: []string{"a", "b", "c"}},
+		{order: action.OrderPushed, expected: []string{"c", "a", "b"}},
+		{order: action.OrderStars, expected: []string{"c", "a", "b"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.order, func(t *testing.T) {
+			var mutex sync.Mutex
+			var processed []string
+			gitService := new(actionfakes.FakeGitService)
+			gitService.GetTreeStub = func(ctx context.Context, owner, repo, sha string, recursive bool) (*github.Tree, *github.Response, error) {
+				mutex.Lock()
+				defer mutex.Unlock()
+				processed = append(processed, repo)
+				return fakeTree("Dockerfile"), &github.Response{}, nil
+			}
+
+			less, err := action.JobOrder(tc.order)
+			require.NoError(t, err)
+
+			var opts []worker.Option
+			if less != nil {
+				opts = append(opts, worker.WithOrder(less))
+			}
+
+			actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, worker.NewWorkerPool(1, opts...), action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+			_, failures, err := actionManager.Drift(ctx, action.RepositoryFilter{})
+
+			require.NoError(t, err)
+			assert.Equal(t, 0, failures)
+			assert.Equal(t, tc.expected, processed)
+		})
+	}
+
+	t.Run("Unknown", func(t *testing.T) {
+		_, err := action.JobOrder("random")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,103 @@
+package action
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+
+	"github.com/google/go-github/v29/github"
+	"gopkg.in/yaml.v3"
+)
+
+type Overrides struct {
+	Defaults map[string]interface{} `yaml:"defaults"`
+	Rules    []OverrideRule         `yaml:"overrides"`
+}
+
+type OverrideRule struct {
+	Name         string                 `yaml:"name"`
+	Repositories []string               `yaml:"repositories"`
+	Topics       []string               `yaml:"topics"`
+	Values       map[string]interface{} `yaml:"values"`
+}
+
+func LoadOverrides(file string) (*Overrides, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var overrides Overrides
+	err = yaml.Unmarshal(data, &overrides)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+
+	for i, rule := range overrides.Rules {
+		if rule.Name == "" {
+			overrides.Rules[i].Name = fmt.Sprintf("overrides[%d]", i)
+		}
+		for _, pattern := range rule.Repositories {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid repository pattern %q in %s: %w", pattern, overrides.Rules[i].Name, err)
+			}
+		}
+	}
+
+	return &overrides, nil
+}
+
+// Values returns the template values for the repository, with the values of
+// every matching rule merged over the defaults in the order the rules are listed.
+// The names of the matching rules are returned alongside.
+func (o *Overrides) Values(repository *github.Repository) (map[string]interface{}, []string) {
+	values := make(map[string]interface{})
+	if o == nil {
+		return values, nil
+	}
+
+	mergeValues(values, o.Defaults)
+
+	var applied []string
+	for _, rule := range o.Rules {
+		if rule.Matches(repository) {
+			mergeValues(values, rule.Values)
+			applied = append(applied, rule.Name)
+		}
+	}
+
+	return values, applied
+}
+
+func (r *OverrideRule) Matches(repository *github.Repository) bool {
+	for _, pattern := range r.Repositories {
+		if matched, _ := path.Match(pattern, repository.GetName()); matched {
+			return true
+		}
+	}
+
+	for _, topic := range r.Topics {
+		for _, t := range repository.Topics {
+			if topic == t {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func mergeValues(dst, src map[string]interface{}) {
+	for key, value := range src {
+		srcMap, srcOk := value.(map[string]interface{})
+		dstMap, dstOk := dst[key].(map[string]interface{})
+		if srcOk && dstOk {
+			merged := make(map[string]interface{}, len(dstMap))
+			mergeValues(merged, dstMap)
+			mergeValues(merged, srcMap)
+			dst[key] = merged
+			continue
+		}
+		dst[key] = value
+	}
+}
@@ -0,0 +1,72 @@
+package action_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/google/go-github/v29/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jace-ys/mobydick-action/bin/pkg/action"
+)
+
+const overridesFile = `
+defaults:
+  runsOn: ubuntu-latest
+  with:
+    fail: true
+overrides:
+  - name: self-hosted
+    repositories: ["infra-*"]
+    values:
+      runsOn: self-hosted
+  - name: gpu
+    topics: ["gpu"]
+    values:
+      with:
+        gpu: true
+`
+
+func TestOverrides(t *testing.T) {
+	file, err := ioutil.TempFile("", "overrides")
+	require.NoError(t, err)
+	defer os.Remove(file.Name())
+
+	_, err = file.WriteString(overridesFile)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	overrides, err := action.LoadOverrides(file.Name())
+	require.NoError(t, err)
+
+	t.Run("Defaults", func(t *testing.T) {
+		values, applied := overrides.Values(&github.Repository{Name: github.String("service")})
+
+		assert.Empty(t, applied)
+		assert.Equal(t, "ubuntu-latest", values["runsOn"])
+	})
+
+	t.Run("Repositories", func(t *testing.T) {
+		values, applied := overrides.Values(&github.Repository{Name: github.String("infra-dns")})
+
+		assert.Equal(t, []string{"self-hosted"}, applied)
+		assert.Equal(t, "self-hosted", values["runsOn"])
+	})
+
+	t.Run("Topics", func(t *testing.T) {
+		values, applied := overrides.Values(&github.Repository{Name: github.String("infra-ml"), Topics: []string{"gpu"}})
+
+		assert.Equal(t, []string{"self-hosted", "gpu"}, applied)
+		assert.Equal(t, map[string]interface{}{"fail": true, "gpu": true}, values["with"])
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		var overrides *action.Overrides
+		values, applied := overrides.Values(&github.Repository{Name: github.String("service")})
+
+		assert.Empty(t, applied)
+		assert.Empty(t, values)
+	})
+}
@@ -0,0 +1,176 @@
+package action
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"time"
+
+	"github.com/google/go-github/v29/github"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+const (
+	StatusCreated     = "created"
+	StatusUpdated     = "updated"
+	StatusWouldCreate = "would-create"
+	StatusWouldUpdate = "would-update"
+	StatusUnchanged   = "unchanged"
+	StatusSkipped     = "skipped"
+	StatusFailed      = "failed"
+	StatusAborted     = "aborted"
+)
+
+var ReportStatuses = []string{StatusCreated, StatusUpdated, StatusWouldCreate, StatusWouldUpdate, StatusUnchanged, StatusSkipped, StatusFailed, StatusAborted}
+
+type Report struct {
+	DryRun       bool                `json:"dry_run"`
+	Repositories []*RepositoryReport `json:"repositories"`
+}
+
+type RepositoryReport struct {
+	Repository  string        `json:"repository"`
+	Status      string        `json:"status"`
+	Files       []*FileReport `json:"files,omitempty"`
+	PullRequest string        `json:"pull_request,omitempty"`
+	ActionUses  []string      `json:"action_uses,omitempty"`
+	Commit      string        `json:"commit,omitempty"`
+	Run         *RunReport    `json:"run,omitempty"`
+	Error       string        `json:"error,omitempty"`
+	Wave        int           `json:"wave,omitempty"`
+	CompletedAt *time.Time    `json:"completed_at,omitempty"`
+}
+
+// RunReport is the outcome of the workflow runs triggered by the commit made to
+// a repository.
+type RunReport struct {
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion,omitempty"`
+	URL        string `json:"url,omitempty"`
+}
+
+type FileReport struct {
+	Path   string `json:"path"`
+	Status string `json:"status"`
+	Diff   string `json:"diff,omitempty"`
+}
+
+func (r *Report) Count(status string) int {
+	var count int
+	for _, repository := range r.Repositories {
+		if repository.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+func (r *Report) CountRuns(status string) int {
+	var count int
+	for _, repository := range r.Repositories {
+		if repository.Run != nil && repository.Run.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// runFailures returns the number of repositories in the wave whose runs did not
+// pass, and the number whose runs were verified.
+func (r *Report) runFailures(wave int) (int, int) {
+	var failures, verified int
+	for _, repository := range r.Repositories {
+		if repository.Wave != wave || repository.Run == nil {
+			continue
+		}
+		verified++
+		if repository.Run.Status != RunPassed {
+			failures++
+		}
+	}
+	return failures, verified
+}
+
+func (r *Report) Write(file string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, append(data, '\n'), 0644)
+}
+
+func (r *Report) sort() {
+	sort.Slice(r.Repositories, func(i, j int) bool {
+		return r.Repositories[i].Repository < r.Repositories[j].Repository
+	})
+}
+
+// abort reports the repositories of the waves that were not started, starting
+// with the given wave number, as aborted for the reason given.
+func (r *Report) abort(waves [][]*github.Repository, wave int, reason error) {
+	for i, repositories := range waves {
+		for _, repository := range repositories {
+			r.Repositories = append(r.Repositories, &RepositoryReport{
+				Repository: repository.GetName(),
+				Status:     StatusAborted,
+				Error:      reason.Error(),
+				Wave:       wave + i,
+			})
+		}
+	}
+}
+
+// fileStatus classifies the change a commit makes to a file, as read from the
+// branch before committing.
+func fileStatus(file *RepositoryFile, dryRun bool) string {
+	switch {
+	case file.SHA == "" && dryRun:
+		return StatusWouldCreate
+	case file.SHA == "":
+		return StatusCreated
+	case bytes.Equal(file.Previous, file.Content):
+		return StatusUnchanged
+	case dryRun:
+		return StatusWouldUpdate
+	default:
+		return StatusUpdated
+	}
+}
+
+// repositoryStatus classifies the changes made to a repository by those of its
+// files, with updates to existing files taking precedence over new files.
+func repositoryStatus(files []*FileReport) string {
+	status := StatusUnchanged
+	for _, file := range files {
+		switch file.Status {
+		case StatusUpdated, StatusWouldUpdate:
+			return file.Status
+		case StatusCreated, StatusWouldCreate:
+			status = file.Status
+		}
+	}
+	return status
+}
+
+func unifiedDiff(file *RepositoryFile) (string, error) {
+	from := "a/" + file.Path
+	if file.SHA == "" {
+		from = "/dev/null"
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        diffLines(file.Previous),
+		B:        diffLines(file.Content),
+		FromFile: from,
+		ToFile:   "b/" + file.Path,
+		Context:  3,
+	})
+}
+
+func diffLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	return difflib.SplitLines(string(content))
+}
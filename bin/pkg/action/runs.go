@@ -0,0 +1,169 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/google/go-github/v29/github"
+)
+
+const (
+	RunPassed   = "passed"
+	RunFailed   = "failed"
+	RunTimedOut = "timed-out"
+
+	DefaultRunTimeout      = 15 * time.Minute
+	DefaultRunPollInterval = 15 * time.Second
+)
+
+var RunStatuses = []string{RunPassed, RunFailed, RunTimedOut}
+
+//counterfeiter:generate . ActionsService
+type ActionsService interface {
+	ListWorkflowRunsByFileName(ctx context.Context, owner, repo, workflowFileName string, opts *ListWorkflowRunsOptions) (*WorkflowRuns, *github.Response, error)
+}
+
+type ListWorkflowRunsOptions struct {
+	HeadSHA string
+	github.ListOptions
+}
+
+type WorkflowRuns struct {
+	TotalCount   int            `json:"total_count"`
+	WorkflowRuns []*WorkflowRun `json:"workflow_runs"`
+}
+
+type WorkflowRun struct {
+	ID         int64  `json:"id"`
+	HeadSHA    string `json:"head_sha"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+	HTMLURL    string `json:"html_url"`
+}
+
+// NewActionsService returns an ActionsService for the workflow runs API, which
+// isn't covered by the GitHub client yet.
+func NewActionsService(client *github.Client) ActionsService {
+	return &actionsService{client: client}
+}
+
+type actionsService struct {
+	client *github.Client
+}
+
+func (s *actionsService) ListWorkflowRunsByFileName(ctx context.Context, owner, repo, workflowFileName string, opts *ListWorkflowRunsOptions) (*WorkflowRuns, *github.Response, error) {
+	u := fmt.Sprintf("repos/%v/%v/actions/workflows/%v/runs", owner, repo, url.PathEscape(workflowFileName))
+	if opts != nil {
+		query := url.Values{}
+		if opts.HeadSHA != "" {
+			query.Set("head_sha", opts.HeadSHA)
+		}
+		if opts.Page != 0 {
+			query.Set("page", strconv.Itoa(opts.Page))
+		}
+		if opts.PerPage != 0 {
+			query.Set("per_page", strconv.Itoa(opts.PerPage))
+		}
+		if len(query) > 0 {
+			u += "?" + query.Encode()
+		}
+	}
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	runs := new(WorkflowRuns)
+	response, err := s.client.Do(ctx, req, runs)
+	if err != nil {
+		return nil, response, err
+	}
+
+	return runs, response, nil
+}
+
+// VerifyRun waits for the runs of the workflow triggered by the commit to
+// complete, polling every interval, and reports whether they passed. Runs that
+// haven't completed within the timeout are reported as timed out.
+func (am *ActionManager) VerifyRun(ctx context.Context, repository, workflow, sha string, timeout, interval time.Duration) (*RunReport, error) {
+	if timeout == 0 {
+		timeout = DefaultRunTimeout
+	}
+	if interval == 0 {
+		interval = DefaultRunPollInterval
+	}
+
+	pollCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	report := &RunReport{Status: RunTimedOut}
+	opts := &ListWorkflowRunsOptions{HeadSHA: sha}
+	for {
+		runs, _, err := am.actionsService.ListWorkflowRunsByFileName(pollCtx, am.organisation, repository, path.Base(workflow), opts)
+		if err != nil {
+			// The workflow may not be registered yet right after it is committed
+			level.Info(am.logger).Log("event", "verify_run.failure", "repository", repository, "error", err)
+		} else if completed := runReport(runs, sha, report); completed {
+			level.Info(am.logger).Log("event", "verify_run.completed", "repository", repository, "status", report.Status, "url", report.URL)
+			return report, nil
+		}
+
+		select {
+		case <-pollCtx.Done():
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			level.Info(am.logger).Log("event", "verify_run.timeout", "repository", repository, "timeout", timeout)
+			return report, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// runReport updates the report from the runs for the commit, and returns true
+// once there are runs and all of them have completed. A failed run is reported
+// over the others.
+func runReport(runs *WorkflowRuns, sha string, report *RunReport) bool {
+	var found, failed bool
+	completed := true
+	for _, run := range runs.WorkflowRuns {
+		if run.HeadSHA != sha {
+			continue
+		}
+		found = true
+
+		if run.Status != "completed" {
+			completed = false
+		}
+		if failed {
+			continue
+		}
+
+		report.URL = run.HTMLURL
+		report.Conclusion = run.Conclusion
+		switch run.Conclusion {
+		case "", "success", "neutral", "skipped":
+		default:
+			failed = true
+		}
+	}
+
+	if !found || !completed {
+		return false
+	}
+
+	report.Status = RunPassed
+	if failed {
+		report.Status = RunFailed
+	}
+	return true
+}
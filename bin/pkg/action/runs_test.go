@@ -0,0 +1,91 @@
+package action_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/go-github/v29/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jace-ys/mobydick-action/bin/pkg/action"
+	"github.com/jace-ys/mobydick-action/bin/pkg/action/actionfakes"
+)
+
+func TestVerifyRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logger := log.NewNopLogger()
+	repositoriesService := new(actionfakes.FakeRepositoriesService)
+	gitService := new(actionfakes.FakeGitService)
+	pullRequestsService := new(actionfakes.FakePullRequestsService)
+
+	t.Run("Passed", func(t *testing.T) {
+		actionsService := new(actionfakes.FakeActionsService)
+		actionsService.ListWorkflowRunsByFileNameReturnsOnCall(0, nil, fakeResponse(404), fmt.Errorf("not found"))
+		actionsService.ListWorkflowRunsByFileNameReturnsOnCall(1, fakeWorkflowRuns(&action.WorkflowRun{HeadSHA: "sha", Status: "in_progress"}), &github.Response{}, nil)
+		actionsService.ListWorkflowRunsByFileNameReturnsOnCall(2, fakeWorkflowRuns(
+			&action.WorkflowRun{HeadSHA: "other", Status: "completed", Conclusion: "failure"},
+			&action.WorkflowRun{HeadSHA: "sha", Status: "completed", Conclusion: "success", HTMLURL: "https://github.com/organisation/repository/actions/runs/1"},
+		), &github.Response{}, nil)
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, nil, nil, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService, Actions: actionsService})
+		run, err := actionManager.VerifyRun(ctx, "repository", ".github/workflows/mobydick.yml", "sha", time.Second, time.Millisecond)
+
+		require.NoError(t, err)
+		assert.Equal(t, &action.RunReport{Status: action.RunPassed, Conclusion: "success", URL: "https://github.com/organisation/repository/actions/runs/1"}, run)
+		assert.Equal(t, 3, actionsService.ListWorkflowRunsByFileNameCallCount())
+
+		_, owner, repo, workflow, opts := actionsService.ListWorkflowRunsByFileNameArgsForCall(0)
+		assert.Equal(t, "organisation", owner)
+		assert.Equal(t, "repository", repo)
+		assert.Equal(t, "mobydick.yml", workflow)
+		assert.Equal(t, "sha", opts.HeadSHA)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		actionsService := new(actionfakes.FakeActionsService)
+		actionsService.ListWorkflowRunsByFileNameReturns(fakeWorkflowRuns(
+			&action.WorkflowRun{HeadSHA: "sha", Status: "completed", Conclusion: "failure", HTMLURL: "failed"},
+			&action.WorkflowRun{HeadSHA: "sha", Status: "completed", Conclusion: "success", HTMLURL: "passed"},
+		), &github.Response{}, nil)
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, nil, nil, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService, Actions: actionsService})
+		run, err := actionManager.VerifyRun(ctx, "repository", ".github/workflows/mobydick.yml", "sha", time.Second, time.Millisecond)
+
+		require.NoError(t, err)
+		assert.Equal(t, &action.RunReport{Status: action.RunFailed, Conclusion: "failure", URL: "failed"}, run)
+	})
+
+	t.Run("TimedOut", func(t *testing.T) {
+		actionsService := new(actionfakes.FakeActionsService)
+		actionsService.ListWorkflowRunsByFileNameReturns(fakeWorkflowRuns(&action.WorkflowRun{HeadSHA: "sha", Status: "queued"}), &github.Response{}, nil)
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, nil, nil, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService, Actions: actionsService})
+		run, err := actionManager.VerifyRun(ctx, "repository", ".github/workflows/mobydick.yml", "sha", 20*time.Millisecond, time.Millisecond)
+
+		require.NoError(t, err)
+		assert.Equal(t, action.RunTimedOut, run.Status)
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		actionsService := new(actionfakes.FakeActionsService)
+		actionsService.ListWorkflowRunsByFileNameReturns(fakeWorkflowRuns(), &github.Response{}, nil)
+
+		cancelledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, nil, nil, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService, Actions: actionsService})
+		_, err := actionManager.VerifyRun(cancelledCtx, "repository", ".github/workflows/mobydick.yml", "sha", time.Second, time.Millisecond)
+
+		assert.Error(t, err)
+	})
+}
+
+func fakeWorkflowRuns(runs ...*action.WorkflowRun) *action.WorkflowRuns {
+	return &action.WorkflowRuns{TotalCount: len(runs), WorkflowRuns: runs}
+}
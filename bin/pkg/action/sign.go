@@ -0,0 +1,110 @@
+package action
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v29/github"
+)
+
+const (
+	SigningFormatGPG = "gpg"
+	SigningFormatSSH = "ssh"
+)
+
+var SigningFormats = []string{SigningFormatGPG, SigningFormatSSH}
+
+//counterfeiter:generate . Signer
+type Signer interface {
+	Sign(payload []byte) ([]byte, error)
+}
+
+// NewSigner returns a Signer that creates detached signatures with the local gpg
+// or ssh-keygen programs. For gpg, key is the ID of a secret key in the keyring,
+// and for ssh it is the path to a private key file.
+func NewSigner(format, key string) (Signer, error) {
+	switch format {
+	case SigningFormatGPG:
+		return &commandSigner{name: "gpg", args: []string{"--batch", "--yes", "--armor", "--detach-sign", "--local-user", key}}, nil
+	case SigningFormatSSH:
+		return &commandSigner{name: "ssh-keygen", args: []string{"-Y", "sign", "-n", "git", "-f", key}}, nil
+	default:
+		return nil, fmt.Errorf("unknown signing format %q", format)
+	}
+}
+
+type commandSigner struct {
+	name string
+	args []string
+}
+
+func (s *commandSigner) Sign(payload []byte) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command(s.name, s.args...)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stderr = &stderr
+
+	signature, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s failed: %w: %s", s.name, err, strings.TrimSpace(stderr.String()))
+	}
+	return signature, nil
+}
+
+// signCommit attaches a signature of the commit to it. GitHub verifies the
+// signature against the commit object it creates, so the author and committer
+// are given explicit dates to make that object the same as the one signed.
+func signCommit(signer Signer, commit *github.Commit) error {
+	if commit.Author == nil {
+		return fmt.Errorf("signed commits need an author")
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	author := *commit.Author
+	author.Date = &now
+	commit.Author = &author
+
+	if commit.Committer != nil {
+		committer := *commit.Committer
+		committer.Date = &now
+		commit.Committer = &committer
+	}
+
+	signature, err := signer.Sign(signaturePayload(commit))
+	if err != nil {
+		return fmt.Errorf("failed to sign commit: %w", err)
+	}
+
+	commit.Verification = &github.SignatureVerification{Signature: github.String(string(signature))}
+	return nil
+}
+
+// signaturePayload returns the commit object that GitHub creates for a commit,
+// which is what the signature has to be made over.
+func signaturePayload(commit *github.Commit) []byte {
+	committer := commit.Committer
+	if committer == nil {
+		committer = commit.Author
+	}
+
+	lines := []string{fmt.Sprintf("tree %s", commit.GetTree().GetSHA())}
+	for _, parent := range commit.Parents {
+		lines = append(lines, fmt.Sprintf("parent %s", parent.GetSHA()))
+	}
+	lines = append(lines,
+		fmt.Sprintf("author %s", signatureIdentity(commit.Author)),
+		fmt.Sprintf("committer %s", signatureIdentity(committer)),
+		"",
+		commit.GetMessage(),
+	)
+
+	return []byte(strings.Join(lines, "\n"))
+}
+
+func signatureIdentity(author *github.CommitAuthor) string {
+	date := author.GetDate()
+	return fmt.Sprintf("%s <%s> %d %s", author.GetName(), author.GetEmail(), date.Unix(), date.Format("-0700"))
+}
@@ -0,0 +1,14 @@
+package action
+
+const DefaultTemplateName = "mobydick.yaml"
+
+// DefaultTemplate is the workflow file distributed when no custom template is given.
+var DefaultTemplate = []byte(`on: [push, pull_request]
+
+jobs:
+  mobydick:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v2
+      - uses: jace-ys/mobydick-action@{{ .Ref }}{{ if ne .Ref .Version }} # {{ .Version }}{{ end }}
+`)
@@ -0,0 +1,133 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v29/github"
+	"gopkg.in/yaml.v3"
+)
+
+// ActionRepository is the repository that workflows reference this action by.
+const ActionRepository = "jace-ys/mobydick-action"
+
+// ActionStep is a step of a workflow that uses this action.
+type ActionStep struct {
+	Job   string
+	Index int
+	Uses  string
+}
+
+func (s ActionStep) String() string {
+	return fmt.Sprintf("jobs.%s.steps[%d]", s.Job, s.Index)
+}
+
+// usesAction reports whether a uses reference is to this action, at any version
+// and including actions within its repository.
+func usesAction(uses string) bool {
+	name := strings.SplitN(uses, "@", 2)[0]
+	return strings.EqualFold(name, ActionRepository) || strings.HasPrefix(strings.ToLower(name), ActionRepository+"/")
+}
+
+// actionSteps returns the steps of a parsed workflow that use this action.
+func actionSteps(root *yaml.Node) []ActionStep {
+	jobs := mappingValue(root, "jobs")
+	if jobs == nil || jobs.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var steps []ActionStep
+	for i := 0; i+1 < len(jobs.Content); i += 2 {
+		jobSteps := mappingValue(jobs.Content[i+1], "steps")
+		if jobSteps == nil || jobSteps.Kind != yaml.SequenceNode {
+			continue
+		}
+		for index, step := range jobSteps.Content {
+			uses := mappingValue(step, "uses")
+			if uses != nil && usesAction(uses.Value) {
+				steps = append(steps, ActionStep{Job: jobs.Content[i].Value, Index: index, Uses: uses.Value})
+			}
+		}
+	}
+	return steps
+}
+
+// ListWorkflows returns the paths of the workflow files on ref, or the default
+// branch of the repository if ref is empty.
+func (am *ActionManager) ListWorkflows(ctx context.Context, repository, ref string) ([]string, error) {
+	var opts *github.RepositoryContentGetOptions
+	if ref != "" {
+		opts = &github.RepositoryContentGetOptions{Ref: ref}
+	}
+
+	_, contents, response, err := am.repositoriesService.GetContents(ctx, am.organisation, repository, WorkflowsDirectory, opts)
+	if err != nil {
+		if response != nil && response.Response != nil && response.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var workflows []string
+	for _, content := range contents {
+		ext := path.Ext(content.GetName())
+		if content.GetType() == "file" && (ext == ".yml" || ext == ".yaml") {
+			workflows = append(workflows, content.GetPath())
+		}
+	}
+
+	sort.Strings(workflows)
+	return workflows, nil
+}
+
+// ActionUse is a step using this action in a workflow file of a repository.
+type ActionUse struct {
+	Path string
+	Step ActionStep
+}
+
+func (u ActionUse) String() string {
+	return fmt.Sprintf("%s:%s", u.Path, u.Step)
+}
+
+// FindActionUses returns the steps using this action in the workflows on ref,
+// or the default branch of the repository if ref is empty, other than the
+// workflow at exclude.
+func (am *ActionManager) FindActionUses(ctx context.Context, repository, ref, exclude string) ([]ActionUse, error) {
+	workflows, err := am.ListWorkflows(ctx, repository, ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflows: %w", err)
+	}
+
+	var uses []ActionUse
+	for _, workflow := range workflows {
+		if workflow == exclude {
+			continue
+		}
+
+		file, err := am.GetFile(ctx, repository, workflow, ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get file: %w", err)
+		}
+		if file == nil {
+			continue
+		}
+
+		var document yaml.Node
+		err = yaml.Unmarshal(file.Content, &document)
+		if err != nil || len(document.Content) == 0 {
+			// Workflows that GitHub can't parse either don't run the action
+			continue
+		}
+
+		for _, step := range actionSteps(document.Content[0]) {
+			uses = append(uses, ActionUse{Path: workflow, Step: step})
+		}
+	}
+
+	return uses, nil
+}
@@ -0,0 +1,140 @@
+package action
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-github/v29/github"
+	"gopkg.in/yaml.v3"
+)
+
+var usesPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+(/[^@\s]+)?@[^@\s]+$`)
+
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid workflow: %s", strings.Join(e.Problems, "; "))
+}
+
+// ValidateWorkflow checks that content is a YAML document with the structure of
+// a GitHub Actions workflow.
+func ValidateWorkflow(content []byte) error {
+	var document yaml.Node
+	err := yaml.Unmarshal(content, &document)
+	if err != nil {
+		return &ValidationError{Problems: []string{err.Error()}}
+	}
+
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return &ValidationError{Problems: []string{"workflow must be a mapping"}}
+	}
+	root := document.Content[0]
+
+	var problems []string
+	if mappingValue(root, "on") == nil {
+		problems = append(problems, "missing on")
+	}
+
+	jobs := mappingValue(root, "jobs")
+	if jobs == nil || jobs.Kind != yaml.MappingNode || len(jobs.Content) == 0 {
+		problems = append(problems, "jobs must be a non-empty mapping")
+	} else {
+		for i := 0; i < len(jobs.Content); i += 2 {
+			problems = append(problems, validateJob(jobs.Content[i].Value, jobs.Content[i+1])...)
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func validateJob(name string, job *yaml.Node) []string {
+	if job.Kind != yaml.MappingNode {
+		return []string{fmt.Sprintf("job %s must be a mapping", name)}
+	}
+
+	// Jobs calling a reusable workflow have neither runs-on nor steps
+	if uses := mappingValue(job, "uses"); uses != nil {
+		if !validUses(uses.Value) {
+			return []string{fmt.Sprintf("job %s has malformed uses %q", name, uses.Value)}
+		}
+		return nil
+	}
+
+	var problems []string
+	if mappingValue(job, "runs-on") == nil {
+		problems = append(problems, fmt.Sprintf("job %s is missing runs-on", name))
+	}
+
+	steps := mappingValue(job, "steps")
+	if steps == nil || steps.Kind != yaml.SequenceNode || len(steps.Content) == 0 {
+		return append(problems, fmt.Sprintf("job %s steps must be a non-empty list", name))
+	}
+
+	for i, step := range steps.Content {
+		if step.Kind != yaml.MappingNode {
+			problems = append(problems, fmt.Sprintf("job %s step %d must be a mapping", name, i+1))
+			continue
+		}
+
+		uses := mappingValue(step, "uses")
+		if uses == nil {
+			if mappingValue(step, "run") == nil {
+				problems = append(problems, fmt.Sprintf("job %s step %d must have uses or run", name, i+1))
+			}
+			continue
+		}
+
+		if !validUses(uses.Value) {
+			problems = append(problems, fmt.Sprintf("job %s step %d has malformed uses %q", name, i+1, uses.Value))
+		}
+	}
+
+	return problems
+}
+
+func validUses(uses string) bool {
+	switch {
+	case strings.HasPrefix(uses, "docker://"):
+		return len(uses) > len("docker://")
+	case strings.HasPrefix(uses, "./"):
+		return true
+	default:
+		return usesPattern.MatchString(uses)
+	}
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	_, value := mappingEntry(node, key)
+	return value
+}
+
+func mappingEntry(node *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// Lint renders the template for a sample repository and validates the result.
+func (wt *WorkflowTemplate) Lint() error {
+	repository := &github.Repository{
+		Name:          github.String("example"),
+		DefaultBranch: github.String("main"),
+		Language:      github.String("Go"),
+	}
+
+	workflowFile, err := wt.Render(wt.Variables("example", repository, []string{"Dockerfile"}))
+	if err != nil {
+		return err
+	}
+
+	return ValidateWorkflow(workflowFile.Content)
+}
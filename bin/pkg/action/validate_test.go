@@ -0,0 +1,111 @@
+package action_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jace-ys/mobydick-action/bin/pkg/action"
+)
+
+func TestValidateWorkflow(t *testing.T) {
+	tt := []struct {
+		name    string
+		content string
+		valid   bool
+	}{
+		{
+			name: "Valid",
+			content: `on: [push, pull_request]
+jobs:
+  mobydick:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v2
+      - run: echo ${{ github.sha }}
+      - uses: jace-ys/mobydick-action@v1.0.0
+`,
+			valid: true,
+		},
+		{
+			name: "ReusableWorkflow",
+			content: `on: push
+jobs:
+  build:
+    uses: organisation/workflows/.github/workflows/build.yml@main
+    with:
+      target: release
+  test:
+    uses: ./.github/workflows/test.yml
+  mobydick:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: jace-ys/mobydick-action@v1.0.0
+`,
+			valid: true,
+		},
+		{
+			name: "MalformedReusableWorkflow",
+			content: `on: push
+jobs:
+  build:
+    uses: organisation/workflows/.github/workflows/build.yml
+`,
+		},
+		{
+			name:    "InvalidYAML",
+			content: "on: [push\njobs:",
+		},
+		{
+			name: "MissingOn",
+			content: `jobs:
+  mobydick:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v2
+`,
+		},
+		{
+			name:    "EmptyJobs",
+			content: "on: push\njobs: {}\n",
+		},
+		{
+			name: "MissingRunsOn",
+			content: `on: push
+jobs:
+  mobydick:
+    steps:
+      - uses: actions/checkout@v2
+`,
+		},
+		{
+			name: "MissingSteps",
+			content: `on: push
+jobs:
+  mobydick:
+    runs-on: ubuntu-latest
+`,
+		},
+		{
+			name: "MalformedUses",
+			content: `on: push
+jobs:
+  mobydick:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: jace-ys/mobydick-action
+`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := action.ValidateWorkflow([]byte(tc.content))
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-kit/kit/log/level"
+)
+
+// LatestVersion resolves to the newest release of this action.
+const LatestVersion = "latest"
+
+// ResolveVersion returns the version of this action to distribute, resolving
+// LatestVersion to the tag of its newest release, along with the ref for
+// workflows to use it by, which is the commit SHA of that tag if pin is set.
+func (am *ActionManager) ResolveVersion(ctx context.Context, version string, pin bool) (string, string, error) {
+	owner, repo := actionOwnerRepo()
+
+	if version == LatestVersion {
+		release, _, err := am.repositoriesService.GetLatestRelease(ctx, owner, repo)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to get latest release: %w", err)
+		}
+		version = release.GetTagName()
+	}
+
+	if !pin {
+		return version, version, nil
+	}
+
+	sha, _, err := am.repositoriesService.GetCommitSHA1(ctx, owner, repo, "tags/"+version, "")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get commit SHA of %s: %w", version, err)
+	}
+
+	level.Info(am.logger).Log("event", "resolve_version.pin", "version", version, "sha", sha)
+	return version, sha, nil
+}
+
+func actionOwnerRepo() (string, string) {
+	parts := strings.SplitN(ActionRepository, "/", 2)
+	return parts[0], parts[1]
+}
@@ -0,0 +1,78 @@
+package action_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/go-github/v29/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jace-ys/mobydick-action/bin/pkg/action"
+	"github.com/jace-ys/mobydick-action/bin/pkg/action/actionfakes"
+	"github.com/jace-ys/mobydick-action/bin/pkg/worker"
+)
+
+func TestResolveVersion(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logger := log.NewNopLogger()
+	workerPool := worker.NewWorkerPool(1)
+	gitService := new(actionfakes.FakeGitService)
+	pullRequestsService := new(actionfakes.FakePullRequestsService)
+
+	t.Run("Version", func(t *testing.T) {
+		repositoriesService := new(actionfakes.FakeRepositoriesService)
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, nil, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+		version, ref, err := actionManager.ResolveVersion(ctx, "v1.0.0", false)
+
+		require.NoError(t, err)
+		assert.Equal(t, "v1.0.0", version)
+		assert.Equal(t, "v1.0.0", ref)
+		assert.Equal(t, 0, repositoriesService.GetLatestReleaseCallCount())
+	})
+
+	t.Run("Latest", func(t *testing.T) {
+		repositoriesService := new(actionfakes.FakeRepositoriesService)
+		repositoriesService.GetLatestReleaseReturns(&github.RepositoryRelease{TagName: github.String("v1.2.0")}, &github.Response{}, nil)
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, nil, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+		version, ref, err := actionManager.ResolveVersion(ctx, action.LatestVersion, false)
+
+		require.NoError(t, err)
+		assert.Equal(t, "v1.2.0", version)
+		assert.Equal(t, "v1.2.0", ref)
+		_, owner, repo := repositoriesService.GetLatestReleaseArgsForCall(0)
+		assert.Equal(t, "jace-ys", owner)
+		assert.Equal(t, "mobydick-action", repo)
+	})
+
+	t.Run("LatestPinned", func(t *testing.T) {
+		repositoriesService := new(actionfakes.FakeRepositoriesService)
+		repositoriesService.GetLatestReleaseReturns(&github.RepositoryRelease{TagName: github.String("v1.2.0")}, &github.Response{}, nil)
+		repositoriesService.GetCommitSHA1Returns("0123456789abcdef0123456789abcdef01234567", &github.Response{}, nil)
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, nil, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+		version, ref, err := actionManager.ResolveVersion(ctx, action.LatestVersion, true)
+
+		require.NoError(t, err)
+		assert.Equal(t, "v1.2.0", version)
+		assert.Equal(t, "0123456789abcdef0123456789abcdef01234567", ref)
+		_, _, _, tag, _ := repositoriesService.GetCommitSHA1ArgsForCall(0)
+		assert.Equal(t, "tags/v1.2.0", tag)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		repositoriesService := new(actionfakes.FakeRepositoriesService)
+		repositoriesService.GetCommitSHA1Returns("", &github.Response{}, fmt.Errorf("no such tag"))
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, nil, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+		_, _, err := actionManager.ResolveVersion(ctx, "v9.9.9", true)
+
+		assert.Error(t, err)
+	})
+}
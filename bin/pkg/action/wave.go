@@ -0,0 +1,82 @@
+package action
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v29/github"
+)
+
+// WaveHook is called before each wave after the first, and stops the rollout
+// before that wave if it returns an error.
+type WaveHook func(ctx context.Context, wave, waves int, repositories []*github.Repository) error
+
+// ParseWaves parses a comma-separated list of increasing percentages of the
+// repositories to have reached by the end of each wave, such as 1%,10%,100%.
+func ParseWaves(spec string) ([]float64, error) {
+	var waves []float64
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.TrimSuffix(strings.TrimSpace(field), "%")
+		percentage, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid wave %q: %w", field, err)
+		}
+		if percentage <= 0 || percentage > 100 {
+			return nil, fmt.Errorf("invalid wave %q: must be between 0%% and 100%%", field)
+		}
+		if len(waves) > 0 && percentage <= waves[len(waves)-1] {
+			return nil, fmt.Errorf("invalid wave %q: waves must increase", field)
+		}
+		waves = append(waves, percentage)
+	}
+
+	if waves[len(waves)-1] != 100 {
+		return nil, fmt.Errorf("last wave must reach 100%% of repositories")
+	}
+
+	return waves, nil
+}
+
+// OrderRepositories shuffles the repositories into an order determined by the
+// seed, so that the same seed always picks the same repositories for the first
+// waves, whatever order they are listed in.
+func OrderRepositories(repositories []*github.Repository, seed string) []*github.Repository {
+	keys := make(map[string]string, len(repositories))
+	for _, repository := range repositories {
+		sum := sha256.Sum256([]byte(seed + "/" + repository.GetName()))
+		keys[repository.GetName()] = hex.EncodeToString(sum[:])
+	}
+
+	ordered := make([]*github.Repository, len(repositories))
+	copy(ordered, repositories)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return keys[ordered[i].GetName()] < keys[ordered[j].GetName()]
+	})
+
+	return ordered
+}
+
+// SplitWaves splits the repositories into waves, each taking them up to its
+// percentage of the total, rounded up. Waves that would be empty are dropped.
+func SplitWaves(repositories []*github.Repository, percentages []float64) [][]*github.Repository {
+	var waves [][]*github.Repository
+	var start int
+	for _, percentage := range percentages {
+		end := int(math.Ceil(float64(len(repositories)) * percentage / 100))
+		if end > len(repositories) {
+			end = len(repositories)
+		}
+		if end <= start {
+			continue
+		}
+		waves = append(waves, repositories[start:end])
+		start = end
+	}
+	return waves
+}
@@ -0,0 +1,232 @@
+package action_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/go-github/v29/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jace-ys/mobydick-action/bin/pkg/action"
+	"github.com/jace-ys/mobydick-action/bin/pkg/action/actionfakes"
+	"github.com/jace-ys/mobydick-action/bin/pkg/worker"
+)
+
+func TestParseWaves(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		waves, err := action.ParseWaves("1%, 10%,50,100%")
+
+		require.NoError(t, err)
+		assert.Equal(t, []float64{1, 10, 50, 100}, waves)
+	})
+
+	for _, spec := range []string{"", "ten%,100%", "0%,100%", "50%,10%,100%", "10%,50%", "10%,150%"} {
+		t.Run(fmt.Sprintf("Invalid/%q", spec), func(t *testing.T) {
+			_, err := action.ParseWaves(spec)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSplitWaves(t *testing.T) {
+	repositories := namedRepositories(20)
+
+	t.Run("OrderRepositories", func(t *testing.T) {
+		ordered := action.OrderRepositories(repositories, "seed")
+		reversed := make([]*github.Repository, len(repositories))
+		for i, repository := range repositories {
+			reversed[len(repositories)-1-i] = repository
+		}
+
+		assert.ElementsMatch(t, repositories, ordered)
+		assert.NotEqual(t, repositories, ordered)
+		assert.Equal(t, ordered, action.OrderRepositories(reversed, "seed"))
+		assert.NotEqual(t, ordered, action.OrderRepositories(repositories, "other"))
+	})
+
+	t.Run("SplitWaves", func(t *testing.T) {
+		waves := action.SplitWaves(repositories, []float64{1, 10, 50, 100})
+
+		require.Len(t, waves, 4)
+		assert.Equal(t, repositories[0:1], waves[0])
+		assert.Equal(t, repositories[1:2], waves[1])
+		assert.Equal(t, repositories[2:10], waves[2])
+		assert.Equal(t, repositories[10:20], waves[3])
+	})
+
+	t.Run("SplitWavesEmpty", func(t *testing.T) {
+		waves := action.SplitWaves(repositories[:2], []float64{1, 10, 50, 100})
+
+		require.Len(t, waves, 2)
+		assert.Len(t, waves[0], 1)
+		assert.Len(t, waves[1], 1)
+	})
+}
+
+func TestDistributeWaves(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logger := log.NewNopLogger()
+	pullRequestsService := new(actionfakes.FakePullRequestsService)
+	workflowTemplate := fakeWorkflowTemplate(t)
+
+	gitService := new(actionfakes.FakeGitService)
+	gitService.GetTreeReturns(fakeTree(), &github.Response{}, nil)
+
+	newRepositoriesService := func(failures int) *actionfakes.FakeRepositoriesService {
+		repositoriesService := new(actionfakes.FakeRepositoriesService)
+		repositoriesService.ListByOrgReturns(namedRepositories(10), &github.Response{}, nil)
+		repositoriesService.GetContentsReturns(nil, nil, fakeResponse(http.StatusNotFound), fmt.Errorf("not found"))
+		repositoriesService.CreateFileReturns(&github.RepositoryContentResponse{}, &github.Response{}, nil)
+		for i := 0; i < failures; i++ {
+			repositoriesService.CreateFileReturnsOnCall(i, nil, &github.Response{}, fmt.Errorf("failed to create file"))
+		}
+		return repositoriesService
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		repositoriesService := newRepositoriesService(0)
+
+		var hooks []int
+		opts := action.DistributeOptions{
+			Waves: []float64{10, 50, 100},
+			BeforeWave: func(ctx context.Context, wave, waves int, repositories []*github.Repository) error {
+				hooks = append(hooks, wave)
+				assert.Equal(t, 3, waves)
+				return nil
+			},
+		}
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, worker.NewWorkerPool(2), action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+		report, err := actionManager.Distribute(ctx, opts)
+
+		require.NoError(t, err)
+		assert.Equal(t, []int{2, 3}, hooks)
+		assert.Equal(t, 10, report.Count(action.StatusCreated))
+		assert.Equal(t, 10, repositoriesService.CreateFileCallCount())
+
+		waves := make(map[int]int)
+		for _, repository := range report.Repositories {
+			waves[repository.Wave]++
+			assert.NotNil(t, repository.CompletedAt)
+		}
+		assert.Equal(t, map[int]int{1: 1, 2: 4, 3: 5}, waves)
+	})
+
+	t.Run("FailureRatio", func(t *testing.T) {
+		repositoriesService := newRepositoriesService(1)
+		opts := action.DistributeOptions{
+			Waves:           []float64{10, 50, 100},
+			MaxFailureRatio: 0.5,
+		}
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, worker.NewWorkerPool(2), action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+		report, err := actionManager.Distribute(ctx, opts)
+
+		assert.Error(t, err)
+		assert.Equal(t, 1, repositoriesService.CreateFileCallCount())
+		assert.Equal(t, 1, report.Count(action.StatusFailed))
+		assert.Equal(t, 9, report.Count(action.StatusAborted))
+	})
+
+	t.Run("CircuitBreaker", func(t *testing.T) {
+		repositoriesService := newRepositoriesService(2)
+		opts := action.DistributeOptions{
+			Waves:           []float64{10, 50, 100},
+			MaxFailureRatio: 1,
+		}
+
+		breaker := &worker.CircuitBreaker{MaxConsecutiveFailures: 2}
+		workerPool := worker.NewWorkerPool(1, worker.WithCircuitBreaker(breaker))
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+		report, err := actionManager.Distribute(ctx, opts)
+
+		assert.Error(t, err)
+		assert.Equal(t, 2, repositoriesService.CreateFileCallCount())
+		assert.Equal(t, 2, report.Count(action.StatusFailed))
+		assert.Equal(t, 8, report.Count(action.StatusAborted))
+	})
+
+	t.Run("RunFailureRatio", func(t *testing.T) {
+		repositoriesService := newRepositoriesService(0)
+		repositoriesService.CreateFileReturns(&github.RepositoryContentResponse{Commit: github.Commit{SHA: github.String("sha")}}, &github.Response{}, nil)
+
+		actionsService := new(actionfakes.FakeActionsService)
+		actionsService.ListWorkflowRunsByFileNameReturns(fakeWorkflowRuns(&action.WorkflowRun{HeadSHA: "sha", Status: "completed", Conclusion: "failure"}), &github.Response{}, nil)
+
+		opts := action.DistributeOptions{
+			Waves:           []float64{10, 50, 100},
+			VerifyRuns:      true,
+			RunPollInterval: time.Millisecond,
+		}
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, worker.NewWorkerPool(2), action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService, Actions: actionsService})
+		report, err := actionManager.Distribute(ctx, opts)
+
+		assert.Error(t, err)
+		assert.Equal(t, 1, report.Count(action.StatusCreated))
+		assert.Equal(t, 1, report.CountRuns(action.RunFailed))
+		assert.Equal(t, 9, report.Count(action.StatusAborted))
+	})
+
+	t.Run("RunFailuresWithoutWaves", func(t *testing.T) {
+		repositoriesService := newRepositoriesService(0)
+		repositoriesService.CreateFileReturns(&github.RepositoryContentResponse{Commit: github.Commit{SHA: github.String("sha")}}, &github.Response{}, nil)
+
+		actionsService := new(actionfakes.FakeActionsService)
+		actionsService.ListWorkflowRunsByFileNameReturns(fakeWorkflowRuns(&action.WorkflowRun{HeadSHA: "sha", Status: "completed", Conclusion: "failure"}), &github.Response{}, nil)
+
+		opts := action.DistributeOptions{
+			VerifyRuns:      true,
+			RunPollInterval: time.Millisecond,
+		}
+
+		breaker := &worker.CircuitBreaker{MaxConsecutiveFailures: 2}
+		workerPool := worker.NewWorkerPool(1, worker.WithCircuitBreaker(breaker))
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, workerPool, action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService, Actions: actionsService})
+		report, err := actionManager.Distribute(ctx, opts)
+
+		assert.Error(t, err)
+		assert.Equal(t, 2, report.Count(action.StatusCreated))
+		assert.Equal(t, 2, report.CountRuns(action.RunFailed))
+		assert.Equal(t, 8, report.Count(action.StatusAborted))
+	})
+
+	t.Run("NotConfirmed", func(t *testing.T) {
+		repositoriesService := newRepositoriesService(0)
+		opts := action.DistributeOptions{
+			Waves: []float64{10, 50, 100},
+			BeforeWave: func(ctx context.Context, wave, waves int, repositories []*github.Repository) error {
+				return fmt.Errorf("not confirmed")
+			},
+		}
+
+		actionManager := action.NewActionManager(ctx, logger, "organisation", false, workflowTemplate, worker.NewWorkerPool(2), action.Services{Repositories: repositoriesService, Git: gitService, PullRequests: pullRequestsService})
+		report, err := actionManager.Distribute(ctx, opts)
+
+		assert.Error(t, err)
+		assert.Equal(t, 1, report.Count(action.StatusCreated))
+		assert.Equal(t, 9, report.Count(action.StatusAborted))
+		for _, repository := range report.Repositories {
+			if repository.Status == action.StatusAborted {
+				assert.Contains(t, []int{2, 3}, repository.Wave)
+			}
+		}
+	})
+}
+
+func namedRepositories(num int) []*github.Repository {
+	var repositories []*github.Repository
+	for i := 0; i < num; i++ {
+		repositories = append(repositories, &github.Repository{Name: github.String(fmt.Sprintf("repository-%02d", i))})
+	}
+	return repositories
+}
@@ -3,7 +3,11 @@ package action
 import (
 	"bytes"
 	"fmt"
+	"path"
+	"strings"
 	"text/template"
+
+	"github.com/google/go-github/v29/github"
 )
 
 type WorkflowFile struct {
@@ -11,26 +15,116 @@ type WorkflowFile struct {
 	Content []byte
 }
 
-func NewWorkflowFile(file, version string) (*WorkflowFile, error) {
-	tmpl, err := template.ParseFiles(file)
+// WorkflowTemplate renders the workflow file for each repository. Ref is what
+// workflows use this action by, which is either Version or its commit SHA.
+type WorkflowTemplate struct {
+	Path      string
+	Version   string
+	Ref       string
+	Overrides *Overrides
+	template  *template.Template
+}
+
+const WorkflowsDirectory = ".github/workflows"
+
+type Delimiters struct {
+	Left  string
+	Right string
+}
+
+type Variables struct {
+	Version               string
+	Ref                   string
+	Organisation          string
+	Repository            RepositoryVariables
+	Dockerfiles           []string
+	DockerfileDirectories []string
+	Values                map[string]interface{}
+	Overrides             []string
+}
+
+type RepositoryVariables struct {
+	Name          string
+	DefaultBranch string
+	Language      string
+	Topics        []string
+}
+
+func NewWorkflowTemplate(path string, text []byte, version string, delimiters Delimiters) (*WorkflowTemplate, error) {
+	tmpl, err := template.New(path).
+		Delims(delimiters.Left, delimiters.Right).
+		Option("missingkey=error").
+		Funcs(templateFuncs).
+		Parse(string(text))
 	if err != nil {
 		return nil, err
 	}
 
-	variables := struct {
-		Version string
-	}{
-		Version: version,
+	return &WorkflowTemplate{
+		Path:     path,
+		Version:  version,
+		Ref:      version,
+		template: tmpl,
+	}, nil
+}
+
+func (wt *WorkflowTemplate) Variables(organisation string, repository *github.Repository, dockerfiles []string) *Variables {
+	values, overrides := wt.Overrides.Values(repository)
+
+	return &Variables{
+		Version:      wt.Version,
+		Ref:          wt.Ref,
+		Organisation: organisation,
+		Repository: RepositoryVariables{
+			Name:          repository.GetName(),
+			DefaultBranch: repository.GetDefaultBranch(),
+			Language:      repository.GetLanguage(),
+			Topics:        repository.Topics,
+		},
+		Dockerfiles:           dockerfiles,
+		DockerfileDirectories: dockerfileDirectories(dockerfiles),
+		Values:                values,
+		Overrides:             overrides,
 	}
+}
 
+func (wt *WorkflowTemplate) Render(variables *Variables) (*WorkflowFile, error) {
 	var content bytes.Buffer
-	err = tmpl.Execute(&content, variables)
+	err := wt.template.Execute(&content, variables)
 	if err != nil {
 		return nil, err
 	}
 
 	return &WorkflowFile{
-		Path:    fmt.Sprintf(".github/workflows/%s", file),
+		Path:    wt.Path,
 		Content: content.Bytes(),
 	}, nil
 }
+
+// WorkflowPath returns the destination path for a workflow file, defaulting to
+// the base name of the template under the workflows directory. Workflows are only
+// picked up by GitHub from .yml or .yaml files directly within that directory.
+func WorkflowPath(dest, template string) (string, error) {
+	if dest == "" {
+		dest = path.Join(WorkflowsDirectory, path.Base(template))
+	}
+
+	if path.IsAbs(dest) {
+		return "", fmt.Errorf("destination %q must be a relative path", dest)
+	}
+	for _, segment := range strings.Split(dest, "/") {
+		if segment == ".." {
+			return "", fmt.Errorf("destination %q must not contain '..'", dest)
+		}
+	}
+
+	cleaned := path.Clean(dest)
+	if path.Dir(cleaned) != WorkflowsDirectory {
+		return "", fmt.Errorf("destination %q must be directly within %s", dest, WorkflowsDirectory)
+	}
+	if ext := path.Ext(cleaned); ext != ".yml" && ext != ".yaml" {
+		return "", fmt.Errorf("destination %q must have a .yml or .yaml extension", dest)
+	}
+
+	return cleaned, nil
+}
@@ -0,0 +1,106 @@
+package action_test
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v29/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jace-ys/mobydick-action/bin/pkg/action"
+)
+
+func TestWorkflowTemplate(t *testing.T) {
+	repository := &github.Repository{
+		Name:          github.String("repository"),
+		DefaultBranch: github.String("main"),
+		Topics:        []string{"docker", "go"},
+	}
+
+	t.Run("DefaultTemplate", func(t *testing.T) {
+		workflowTemplate, err := action.NewWorkflowTemplate(".github/workflows/mobydick.yaml", action.DefaultTemplate, "v1.0.0", action.Delimiters{})
+		require.NoError(t, err)
+
+		assert.NoError(t, workflowTemplate.Lint())
+
+		workflowFile, err := workflowTemplate.Render(workflowTemplate.Variables("organisation", repository, nil))
+		require.NoError(t, err)
+		assert.Contains(t, string(workflowFile.Content), "uses: jace-ys/mobydick-action@v1.0.0\n")
+	})
+
+	t.Run("DefaultTemplatePinned", func(t *testing.T) {
+		workflowTemplate, err := action.NewWorkflowTemplate(".github/workflows/mobydick.yaml", action.DefaultTemplate, "v1.0.0", action.Delimiters{})
+		require.NoError(t, err)
+		workflowTemplate.Ref = "0123456789abcdef0123456789abcdef01234567"
+
+		assert.NoError(t, workflowTemplate.Lint())
+
+		workflowFile, err := workflowTemplate.Render(workflowTemplate.Variables("organisation", repository, nil))
+		require.NoError(t, err)
+		assert.Contains(t, string(workflowFile.Content), "uses: jace-ys/mobydick-action@0123456789abcdef0123456789abcdef01234567 # v1.0.0\n")
+	})
+
+	t.Run("Delimiters", func(t *testing.T) {
+		text := []byte("token: ${{ secrets.TOKEN }}\nuses: jace-ys/mobydick-action@[[ .Version ]]")
+		workflowTemplate, err := action.NewWorkflowTemplate("workflow.yaml", text, "v1.0.0", action.Delimiters{Left: "[[", Right: "]]"})
+		require.NoError(t, err)
+
+		workflowFile, err := workflowTemplate.Render(workflowTemplate.Variables("organisation", repository, nil))
+		require.NoError(t, err)
+		assert.Equal(t, "token: ${{ secrets.TOKEN }}\nuses: jace-ys/mobydick-action@v1.0.0", string(workflowFile.Content))
+	})
+
+	t.Run("MissingKey", func(t *testing.T) {
+		text := []byte("runs-on: {{ .Values.runsOn }}")
+		workflowTemplate, err := action.NewWorkflowTemplate("workflow.yaml", text, "v1.0.0", action.Delimiters{})
+		require.NoError(t, err)
+
+		_, err = workflowTemplate.Render(workflowTemplate.Variables("organisation", repository, nil))
+		assert.Error(t, err)
+	})
+
+	t.Run("Funcs", func(t *testing.T) {
+		text := []byte(`name: {{ quote .Repository.Name }}
+runs-on: {{ default "ubuntu-latest" (index .Values "runsOn") }}
+topics:
+{{ toYaml .Repository.Topics | indent 2 }}`)
+		workflowTemplate, err := action.NewWorkflowTemplate("workflow.yaml", text, "v1.0.0", action.Delimiters{})
+		require.NoError(t, err)
+
+		workflowFile, err := workflowTemplate.Render(workflowTemplate.Variables("organisation", repository, nil))
+		require.NoError(t, err)
+		assert.Equal(t, "name: \"repository\"\nruns-on: ubuntu-latest\ntopics:\n  - docker\n  - go", string(workflowFile.Content))
+	})
+}
+
+func TestWorkflowPath(t *testing.T) {
+	tt := []struct {
+		name     string
+		dest     string
+		template string
+		path     string
+		valid    bool
+	}{
+		{name: "Default", template: "templates/mobydick.yaml", path: ".github/workflows/mobydick.yaml", valid: true},
+		{name: "Dest", dest: ".github/workflows/docker.yml", template: "mobydick.yaml", path: ".github/workflows/docker.yml", valid: true},
+		{name: "Absolute", dest: "/.github/workflows/mobydick.yaml"},
+		{name: "Parent", dest: ".github/workflows/../workflows/mobydick.yaml"},
+		{name: "Subdirectory", dest: ".github/workflows/templates/mobydick.yaml"},
+		{name: "OutsideWorkflows", dest: "mobydick.yaml"},
+		{name: "Extension", dest: ".github/workflows/mobydick.json"},
+		{name: "DefaultExtension", template: "templates/mobydick.tmpl"},
+		{name: "DefaultYml", template: "mobydick.yml", path: ".github/workflows/mobydick.yml", valid: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			path, err := action.WorkflowPath(tc.dest, tc.template)
+			if tc.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.path, path)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package action
+
+import (
+	"bytes"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// insertIntoSequence appends items to the block sequence held by key by editing
+// the document text directly, so that the formatting and comments around it are
+// left untouched. The items are expected as a YAML sequence indented from column
+// zero. It returns false if the sequence is not a non-empty block sequence.
+func insertIntoSequence(content []byte, key, sequence *yaml.Node, items []byte) ([]byte, bool) {
+	if sequence.Kind != yaml.SequenceNode || sequence.Style&yaml.FlowStyle != 0 || len(sequence.Content) == 0 {
+		return nil, false
+	}
+
+	lines := splitLines(content)
+	first := sequence.Content[0]
+	dash := strings.LastIndex(lines[first.Line-1][:first.Column-1], "-")
+	if dash < 0 {
+		return nil, false
+	}
+
+	end := blockEnd(lines, key.Line, key.Column-1)
+	return insertLines(lines, end, indentLines(items, dash)), true
+}
+
+// insertIntoMapping appends entries to the block mapping held by key in the same
+// way as insertIntoSequence. The entries are expected as a YAML mapping indented
+// from column zero. It returns false if the mapping is not a non-empty block
+// mapping.
+func insertIntoMapping(content []byte, key, mapping *yaml.Node, entries []byte) ([]byte, bool) {
+	if mapping.Kind != yaml.MappingNode || mapping.Style&yaml.FlowStyle != 0 || len(mapping.Content) == 0 {
+		return nil, false
+	}
+
+	lines := splitLines(content)
+	end := blockEnd(lines, key.Line, key.Column-1)
+	return insertLines(lines, end, indentLines(entries, mapping.Content[0].Column-1)), true
+}
+
+// blockEnd returns the index of the line following the last content line of the
+// block value belonging to the key on line (1-based) at the given indentation.
+// Sequence items may share the indentation of their key.
+func blockEnd(lines []string, line, indent int) int {
+	end := line
+	for i := line; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		lineIndent := len(lines[i]) - len(strings.TrimLeft(lines[i], " "))
+		if lineIndent < indent || (lineIndent == indent && !strings.HasPrefix(trimmed, "-")) {
+			break
+		}
+		end = i + 1
+	}
+	return end
+}
+
+func splitLines(content []byte) []string {
+	text := strings.TrimSuffix(string(content), "\n")
+	return strings.Split(text, "\n")
+}
+
+func indentLines(text []byte, spaces int) []string {
+	lines := splitLines(text)
+	padding := strings.Repeat(" ", spaces)
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = padding + line
+		}
+	}
+	return lines
+}
+
+func insertLines(lines []string, at int, inserted []string) []byte {
+	var buf bytes.Buffer
+	for _, line := range lines[:at] {
+		buf.WriteString(line + "\n")
+	}
+	for _, line := range inserted {
+		buf.WriteString(line + "\n")
+	}
+	for _, line := range lines[at:] {
+		buf.WriteString(line + "\n")
+	}
+	return buf.Bytes()
+}
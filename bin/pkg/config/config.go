@@ -0,0 +1,227 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	DefaultFile = "mobydick-cli.yaml"
+	ConfigFlag  = "config"
+	ProfileFlag = "profile"
+)
+
+type Config struct {
+	Flags    Values             `yaml:"flags"`
+	Commands map[string]Values  `yaml:"commands"`
+	Profiles map[string]Profile `yaml:"profiles"`
+}
+
+type Profile struct {
+	Flags    Values            `yaml:"flags"`
+	Commands map[string]Values `yaml:"commands"`
+}
+
+type Values map[string]Value
+
+type Value []string
+
+func (v *Value) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		*v = Value{node.Value}
+	case yaml.SequenceNode:
+		var values Value
+		for _, item := range node.Content {
+			if item.Kind != yaml.ScalarNode {
+				return fmt.Errorf("line %d: flag values must be scalars", item.Line)
+			}
+			values = append(values, item.Value)
+		}
+		*v = values
+	default:
+		return fmt.Errorf("line %d: flag values must be a scalar or a list of scalars", node.Line)
+	}
+	return nil
+}
+
+func Load(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var config Config
+	err = yaml.Unmarshal(data, &config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return &config, nil
+}
+
+func Find(path string) (string, error) {
+	if path != "" {
+		return path, nil
+	}
+
+	_, err := os.Stat(DefaultFile)
+	if os.IsNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	return DefaultFile, nil
+}
+
+// Apply returns args extended with the flag values supplied by the configuration
+// file for the selected command and profile. Flags given explicitly in args are
+// never overridden.
+func Apply(app *kingpin.Application, args []string) ([]string, error) {
+	context, err := app.ParseContext(args)
+	if err != nil || context.SelectedCommand == nil {
+		return args, nil
+	}
+
+	var path, profile string
+	explicit := make(map[string]bool)
+	flags := make(map[string]*kingpin.FlagModel)
+	for _, flag := range app.Model().Flags {
+		flags[flag.Name] = flag
+	}
+	for _, element := range context.Elements {
+		switch clause := element.Clause.(type) {
+		case *kingpin.CmdClause:
+			for _, flag := range clause.Model().Flags {
+				flags[flag.Name] = flag
+			}
+		case *kingpin.FlagClause:
+			name := clause.Model().Name
+			explicit[name] = true
+			switch name {
+			case ConfigFlag:
+				path = *element.Value
+			case ProfileFlag:
+				profile = *element.Value
+			}
+		}
+	}
+
+	path, err = Find(path)
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		if profile != "" {
+			return nil, fmt.Errorf("profile %q selected without a configuration file", profile)
+		}
+		return args, nil
+	}
+
+	config, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := config.Resolve(context.SelectedCommand.FullCommand(), profile, flags)
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration file %s: %w", path, err)
+	}
+
+	var names []string
+	for name := range values {
+		if !explicit[name] && name != ConfigFlag && name != ProfileFlag {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var extra []string
+	for _, name := range names {
+		if flags[name].IsBoolFlag() {
+			for _, value := range values[name] {
+				enabled, err := strconv.ParseBool(value)
+				if err != nil {
+					return nil, fmt.Errorf("invalid configuration file %s: flag %q expects a boolean", path, name)
+				}
+				if enabled {
+					extra = append(extra, "--"+name)
+				} else {
+					extra = append(extra, "--no-"+name)
+				}
+			}
+			continue
+		}
+		for _, value := range values[name] {
+			extra = append(extra, fmt.Sprintf("--%s=%s", name, value))
+		}
+	}
+
+	return insertFlags(args, extra), nil
+}
+
+// Resolve merges the flag values that apply to the given command, in increasing
+// order of precedence: shared flags, command flags, profile flags and profile
+// command flags. Shared flags that the command does not define are ignored.
+func (c *Config) Resolve(command, profile string, flags map[string]*kingpin.FlagModel) (Values, error) {
+	sections := []section{
+		{"flags", c.Flags, false},
+		{"commands." + command, c.Commands[command], true},
+	}
+
+	if profile != "" {
+		p, ok := c.Profiles[profile]
+		if !ok {
+			return nil, fmt.Errorf("unknown profile %q", profile)
+		}
+		prefix := "profiles." + profile
+		sections = append(sections,
+			section{prefix + ".flags", p.Flags, false},
+			section{prefix + ".commands." + command, p.Commands[command], true},
+		)
+	}
+
+	resolved := make(Values)
+	for _, section := range sections {
+		for name, value := range section.values {
+			if _, ok := flags[name]; !ok {
+				if section.strict {
+					return nil, fmt.Errorf("unknown flag %q in %s", name, section.name)
+				}
+				continue
+			}
+			resolved[name] = value
+		}
+	}
+
+	return resolved, nil
+}
+
+type section struct {
+	name   string
+	values Values
+	strict bool
+}
+
+func insertFlags(args, flags []string) []string {
+	end := len(args)
+	for i, arg := range args {
+		if arg == "--" {
+			end = i
+			break
+		}
+	}
+
+	result := make([]string, 0, len(args)+len(flags))
+	result = append(result, args[:end]...)
+	result = append(result, flags...)
+	result = append(result, args[end:]...)
+	return result
+}
@@ -0,0 +1,133 @@
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/jace-ys/mobydick-action/bin/pkg/config"
+)
+
+const configFile = `
+flags:
+  organisation: shared-org
+  concurrency: 2
+commands:
+  distribute:
+    file: workflow.yaml
+    private: true
+profiles:
+  prod-org:
+    flags:
+      organisation: prod-org
+    commands:
+      distribute:
+        concurrency: 10
+        private: false
+`
+
+type testApp struct {
+	app          *kingpin.Application
+	distribute   *kingpin.CmdClause
+	organisation *string
+	concurrency  *int
+	file         *string
+	private      *bool
+}
+
+func newTestApp() *testApp {
+	a := &testApp{app: kingpin.New("test", "")}
+	a.organisation = a.app.Flag("organisation", "").Required().String()
+	a.app.Flag(config.ConfigFlag, "").String()
+	a.app.Flag(config.ProfileFlag, "").String()
+	a.distribute = a.app.Command("distribute", "")
+	a.concurrency = a.distribute.Flag("concurrency", "").Default("5").Int()
+	a.file = a.distribute.Flag("file", "").Default("mobydick.yaml").String()
+	a.private = a.distribute.Flag("private", "").Default("false").Bool()
+	a.app.Command("list", "")
+	return a
+}
+
+func TestApply(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, config.DefaultFile)
+	require.NoError(t, ioutil.WriteFile(path, []byte(configFile), 0644))
+
+	t.Run("Defaults", func(t *testing.T) {
+		a := newTestApp()
+		args, err := config.Apply(a.app, []string{"distribute", "--config", path})
+		require.NoError(t, err)
+
+		_, err = a.app.Parse(args)
+		require.NoError(t, err)
+		assert.Equal(t, "shared-org", *a.organisation)
+		assert.Equal(t, 2, *a.concurrency)
+		assert.Equal(t, "workflow.yaml", *a.file)
+		assert.True(t, *a.private)
+	})
+
+	t.Run("Profile", func(t *testing.T) {
+		a := newTestApp()
+		args, err := config.Apply(a.app, []string{"distribute", "--config", path, "--profile", "prod-org"})
+		require.NoError(t, err)
+
+		_, err = a.app.Parse(args)
+		require.NoError(t, err)
+		assert.Equal(t, "prod-org", *a.organisation)
+		assert.Equal(t, 10, *a.concurrency)
+		assert.False(t, *a.private)
+	})
+
+	t.Run("ExplicitFlags", func(t *testing.T) {
+		a := newTestApp()
+		args, err := config.Apply(a.app, []string{"--organisation", "flag-org", "distribute", "--config", path, "--concurrency=1", "--no-private"})
+		require.NoError(t, err)
+
+		_, err = a.app.Parse(args)
+		require.NoError(t, err)
+		assert.Equal(t, "flag-org", *a.organisation)
+		assert.Equal(t, 1, *a.concurrency)
+		assert.False(t, *a.private)
+	})
+
+	t.Run("SharedFlagsIgnored", func(t *testing.T) {
+		a := newTestApp()
+		args, err := config.Apply(a.app, []string{"list", "--config", path})
+		require.NoError(t, err)
+
+		_, err = a.app.Parse(args)
+		require.NoError(t, err)
+		assert.Equal(t, "shared-org", *a.organisation)
+	})
+
+	t.Run("UnknownProfile", func(t *testing.T) {
+		a := newTestApp()
+		_, err := config.Apply(a.app, []string{"distribute", "--config", path, "--profile", "unknown"})
+		assert.Error(t, err)
+	})
+
+	t.Run("UnknownFlag", func(t *testing.T) {
+		invalid := filepath.Join(dir, "invalid.yaml")
+		require.NoError(t, ioutil.WriteFile(invalid, []byte("commands:\n  distribute:\n    unknown: value\n"), 0644))
+
+		a := newTestApp()
+		_, err := config.Apply(a.app, []string{"distribute", "--config", invalid})
+		assert.Error(t, err)
+	})
+
+	t.Run("NoConfig", func(t *testing.T) {
+		a := newTestApp()
+		args := []string{"--organisation", "flag-org", "distribute"}
+		applied, err := config.Apply(a.app, args)
+		require.NoError(t, err)
+		assert.Equal(t, args, applied)
+	})
+}
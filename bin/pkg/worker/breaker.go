@@ -0,0 +1,91 @@
+package worker
+
+import (
+	"fmt"
+	"sync"
+)
+
+// CircuitBreaker stops a worker pool from dispatching more jobs once too many
+// have failed, either in a row or as a ratio of the most recent results. Until
+// Window jobs have completed, the failures so far still count against the whole
+// window. A zero threshold disables that check.
+type CircuitBreaker struct {
+	MaxConsecutiveFailures int
+	MaxFailureRatio        float64
+	Window                 int
+
+	mutex       sync.Mutex
+	consecutive int
+	recent      []bool
+	err         error
+}
+
+// BreakerError is the error jobs are aborted with once the circuit breaker
+// has opened, wrapping the error of the job that opened it.
+type BreakerError struct {
+	Reason string
+	Err    error
+}
+
+func (e *BreakerError) Error() string {
+	return fmt.Sprintf("circuit breaker opened after %s: %s", e.Reason, e.Err)
+}
+
+func (e *BreakerError) Unwrap() error {
+	return e.Err
+}
+
+func (b *CircuitBreaker) reset() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.consecutive = 0
+	b.recent = nil
+	b.err = nil
+}
+
+// record counts the result of a job, opening the breaker if it fails one job
+// too many.
+func (b *CircuitBreaker) record(err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.err != nil {
+		return
+	}
+
+	if err != nil {
+		b.consecutive++
+	} else {
+		b.consecutive = 0
+	}
+
+	if b.Window > 0 {
+		b.recent = append(b.recent, err != nil)
+		if len(b.recent) > b.Window {
+			b.recent = b.recent[1:]
+		}
+	}
+
+	switch {
+	case b.MaxConsecutiveFailures > 0 && b.consecutive >= b.MaxConsecutiveFailures:
+		b.err = &BreakerError{Reason: fmt.Sprintf("%d consecutive failures", b.consecutive), Err: err}
+	case b.MaxFailureRatio > 0 && b.Window > 0 && err != nil:
+		var failures int
+		for _, failed := range b.recent {
+			if failed {
+				failures++
+			}
+		}
+		if float64(failures)/float64(b.Window) > b.MaxFailureRatio {
+			b.err = &BreakerError{Reason: fmt.Sprintf("%d failures in the last %d jobs", failures, b.Window), Err: err}
+		}
+	}
+}
+
+// Err returns the error that opened the breaker, or nil if it is closed.
+func (b *CircuitBreaker) Err() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.err
+}
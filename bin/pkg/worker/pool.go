@@ -3,7 +3,9 @@ package worker
 
 import (
 	"context"
+	"sort"
 	"sync"
+	"time"
 )
 
 //counterfeiter:generate . Job
@@ -11,29 +13,86 @@ type Job interface {
 	Process(ctx context.Context) error
 }
 
+// Result is the outcome of a job, and when it completed. Jobs that were never
+// processed because the circuit breaker opened are Aborted, with the error that
+// opened it.
 type Result struct {
-	Job Job
-	Err error
+	Job       Job
+	Err       error
+	Aborted   bool
+	Completed time.Time
+	index     int
+}
+
+type Option func(*WorkerPool)
+
+// WithCircuitBreaker stops the pool from processing more jobs once the breaker
+// opens.
+func WithCircuitBreaker(breaker *CircuitBreaker) Option {
+	return func(p *WorkerPool) {
+		p.breaker = breaker
+	}
+}
+
+// WithOrder dispatches jobs in the order given by less instead of the order
+// they are passed to Work in. Results are still returned in the order the jobs
+// were passed in.
+func WithOrder(less func(a, b Job) bool) Option {
+	return func(p *WorkerPool) {
+		p.less = less
+	}
+}
+
+// WithRateLimiter waits for the rate limiter before processing each job.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(p *WorkerPool) {
+		p.limiter = limiter
+	}
 }
 
 type WorkerPool struct {
 	concurrency int
-	jobsChan    chan Job
+	breaker     *CircuitBreaker
+	limiter     *RateLimiter
+	less        func(a, b Job) bool
+	jobs        []Job
+	jobsChan    chan int
 	resultsChan chan Result
 	waitGroup   sync.WaitGroup
 }
 
-func NewWorkerPool(concurrency int) *WorkerPool {
-	return &WorkerPool{
+func NewWorkerPool(concurrency int, opts ...Option) *WorkerPool {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	p := &WorkerPool{
 		concurrency: concurrency,
-		jobsChan:    make(chan Job, concurrency),
 	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
 }
 
+// Work processes the jobs and returns their results in the same order once all
+// are done. It can be called again with more jobs after it returns, and the
+// circuit breaker carries on counting from where it left off until Reset.
 func (p *WorkerPool) Work(ctx context.Context, jobs []Job) []Result {
+	order := make([]int, len(jobs))
+	for i := range order {
+		order[i] = i
+	}
+	if p.less != nil {
+		sort.SliceStable(order, func(i, j int) bool {
+			return p.less(jobs[order[i]], jobs[order[j]])
+		})
+	}
+
+	p.jobs = jobs
+	p.jobsChan = make(chan int, p.concurrency)
 	p.resultsChan = make(chan Result, len(jobs))
 
-	p.waitGroup.Add(p.concurrency)
+	p.waitGroup.Add(p.concurrency + 1)
 	for i := 0; i < p.concurrency; i++ {
 		go func() {
 			defer p.waitGroup.Done()
@@ -42,9 +101,14 @@ func (p *WorkerPool) Work(ctx context.Context, jobs []Job) []Result {
 	}
 
 	go func() {
+		defer p.waitGroup.Done()
 		defer close(p.jobsChan)
-		for _, job := range jobs {
-			p.jobsChan <- job
+		for _, index := range order {
+			if err := p.Err(); err != nil {
+				p.resultsChan <- Result{Job: jobs[index], Err: err, Aborted: true, index: index}
+				continue
+			}
+			p.jobsChan <- index
 		}
 	}()
 
@@ -57,16 +121,48 @@ func (p *WorkerPool) Work(ctx context.Context, jobs []Job) []Result {
 }
 
 func (p *WorkerPool) Results() []Result {
-	var results []Result
+	results := make([]Result, len(p.jobs))
 	for r := range p.resultsChan {
-		results = append(results, r)
+		results[r.index] = r
 	}
 	return results
 }
 
+// Reset closes the circuit breaker and clears the results it has counted.
+func (p *WorkerPool) Reset() {
+	if p.breaker != nil {
+		p.breaker.reset()
+	}
+}
+
+// Err returns the error that opened the circuit breaker since the last Reset,
+// or nil if it didn't open.
+func (p *WorkerPool) Err() error {
+	if p.breaker == nil {
+		return nil
+	}
+	return p.breaker.Err()
+}
+
 func (p *WorkerPool) startWorker(ctx context.Context) {
-	for job := range p.jobsChan {
+	for index := range p.jobsChan {
+		job := p.jobs[index]
+		if err := p.Err(); err != nil {
+			p.resultsChan <- Result{Job: job, Err: err, Aborted: true, index: index}
+			continue
+		}
+
+		if p.limiter != nil {
+			if err := p.limiter.Wait(ctx); err != nil {
+				p.resultsChan <- Result{Job: job, Err: err, Completed: time.Now(), index: index}
+				continue
+			}
+		}
+
 		err := job.Process(ctx)
-		p.resultsChan <- Result{job, err}
+		if p.breaker != nil {
+			p.breaker.record(err)
+		}
+		p.resultsChan <- Result{Job: job, Err: err, Completed: time.Now(), index: index}
 	}
 }
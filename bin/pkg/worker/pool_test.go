@@ -2,6 +2,7 @@ package worker_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -68,4 +69,166 @@ func TestWorkerPool(t *testing.T) {
 		}
 		assert.WithinDuration(t, start, end, time.Duration(numOfJobs/concurrency+1)*time.Second)
 	})
+
+	t.Run("Work/ZeroConcurrency", func(t *testing.T) {
+		workerPool := worker.NewWorkerPool(0)
+
+		job := new(workerfakes.FakeJob)
+		results := workerPool.Work(ctx, []worker.Job{job})
+
+		assert.Len(t, results, 1)
+		assert.Equal(t, 1, job.ProcessCallCount())
+	})
+
+	t.Run("Work/Reuse", func(t *testing.T) {
+		workerPool := worker.NewWorkerPool(concurrency)
+
+		for run := 0; run < 2; run++ {
+			jobs := make([]worker.Job, numOfJobs)
+			for i := 0; i < numOfJobs; i++ {
+				jobs[i] = new(workerfakes.FakeJob)
+			}
+
+			results := workerPool.Work(ctx, jobs)
+
+			assert.Len(t, results, numOfJobs)
+			for i := 0; i < numOfJobs; i++ {
+				assert.Equal(t, 1, jobs[i].(*workerfakes.FakeJob).ProcessCallCount())
+			}
+		}
+	})
+
+	t.Run("Work/CircuitBreaker", func(t *testing.T) {
+		newJobs := func(fail func(i int) bool) []worker.Job {
+			jobs := make([]worker.Job, numOfJobs)
+			for i := 0; i < numOfJobs; i++ {
+				job := new(workerfakes.FakeJob)
+				if fail(i) {
+					job.ProcessReturns(fmt.Errorf("error processing job %d", i))
+				}
+				jobs[i] = job
+			}
+			return jobs
+		}
+
+		t.Run("Consecutive", func(t *testing.T) {
+			breaker := &worker.CircuitBreaker{MaxConsecutiveFailures: 3}
+			workerPool := worker.NewWorkerPool(1, worker.WithCircuitBreaker(breaker))
+
+			jobs := newJobs(func(i int) bool { return i != 1 })
+			results := workerPool.Work(ctx, jobs)
+
+			var processed, aborted int
+			for _, result := range results {
+				if result.Aborted {
+					aborted++
+					assert.Equal(t, workerPool.Err(), result.Err)
+					continue
+				}
+				processed++
+			}
+			assert.Equal(t, 5, processed)
+			assert.Equal(t, numOfJobs-5, aborted)
+			assert.EqualError(t, errors.Unwrap(workerPool.Err()), "error processing job 4")
+			assert.Equal(t, 0, jobs[5].(*workerfakes.FakeJob).ProcessCallCount())
+		})
+
+		t.Run("Ratio", func(t *testing.T) {
+			breaker := &worker.CircuitBreaker{MaxFailureRatio: 0.5, Window: 4}
+			workerPool := worker.NewWorkerPool(1, worker.WithCircuitBreaker(breaker))
+
+			results := workerPool.Work(ctx, newJobs(func(i int) bool { return i%3 != 0 }))
+
+			var aborted int
+			for _, result := range results {
+				if result.Aborted {
+					aborted++
+				}
+			}
+			assert.Equal(t, numOfJobs-5, aborted)
+			assert.Error(t, workerPool.Err())
+		})
+
+		t.Run("RatioPartialWindow", func(t *testing.T) {
+			breaker := &worker.CircuitBreaker{MaxFailureRatio: 0.1, Window: 20}
+			workerPool := worker.NewWorkerPool(1, worker.WithCircuitBreaker(breaker))
+
+			jobs := newJobs(func(i int) bool { return true })
+			results := workerPool.Work(ctx, jobs)
+
+			var aborted int
+			for _, result := range results {
+				if result.Aborted {
+					aborted++
+				}
+			}
+			assert.Equal(t, numOfJobs-3, aborted)
+			assert.EqualError(t, errors.Unwrap(workerPool.Err()), "error processing job 2")
+		})
+
+		t.Run("AcrossCalls", func(t *testing.T) {
+			breaker := &worker.CircuitBreaker{MaxConsecutiveFailures: 3}
+			workerPool := worker.NewWorkerPool(1, worker.WithCircuitBreaker(breaker))
+
+			workerPool.Work(ctx, newJobs(func(i int) bool { return i < 2 })[:2])
+			assert.NoError(t, workerPool.Err())
+
+			jobs := newJobs(func(i int) bool { return true })
+			results := workerPool.Work(ctx, jobs)
+
+			assert.Error(t, workerPool.Err())
+			assert.Equal(t, 1, jobs[0].(*workerfakes.FakeJob).ProcessCallCount())
+			assert.Equal(t, 0, jobs[1].(*workerfakes.FakeJob).ProcessCallCount())
+			assert.True(t, results[1].Aborted)
+		})
+
+		t.Run("Closed", func(t *testing.T) {
+			breaker := &worker.CircuitBreaker{MaxConsecutiveFailures: 3, MaxFailureRatio: 0.5, Window: 4}
+			workerPool := worker.NewWorkerPool(1, worker.WithCircuitBreaker(breaker))
+
+			workerPool.Work(ctx, newJobs(func(i int) bool { return true }))
+			assert.Error(t, workerPool.Err())
+
+			workerPool.Reset()
+			results := workerPool.Work(ctx, newJobs(func(i int) bool { return i%2 == 0 }))
+			assert.NoError(t, workerPool.Err())
+			for _, result := range results {
+				assert.False(t, result.Aborted)
+			}
+		})
+	})
+
+	t.Run("Work/Order", func(t *testing.T) {
+		var dispatched []int
+		jobs := make([]worker.Job, numOfJobs)
+		for i := 0; i < numOfJobs; i++ {
+			i := i
+			job := new(workerfakes.FakeJob)
+			job.ProcessStub = func(ctx context.Context) error {
+				dispatched = append(dispatched, i)
+				return nil
+			}
+			jobs[i] = job
+		}
+
+		index := func(job worker.Job) int {
+			for i := range jobs {
+				if jobs[i] == job {
+					return i
+				}
+			}
+			return -1
+		}
+		workerPool := worker.NewWorkerPool(1, worker.WithOrder(func(a, b worker.Job) bool {
+			return index(a) > index(b)
+		}))
+
+		results := workerPool.Work(ctx, jobs)
+
+		assert.Equal(t, []int{9, 8, 7, 6, 5, 4, 3, 2, 1, 0}, dispatched)
+		for i, result := range results {
+			assert.Equal(t, jobs[i], result.Job)
+			assert.False(t, result.Completed.IsZero())
+		}
+	})
 }
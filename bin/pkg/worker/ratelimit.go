@@ -0,0 +1,142 @@
+package worker
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultLowQuota is the number of remaining requests below which a rate
+// limiter starts slowing down.
+const DefaultLowQuota = 500
+
+// RateLimiter is a token bucket letting jobs start at Rate a second on average,
+// in bursts of up to Burst. Once the quota remaining reported by GitHub drops
+// below LowQuota, the rate is scaled down with it, and when the quota runs out,
+// no jobs are started until it is reset.
+type RateLimiter struct {
+	Rate     float64
+	Burst    int
+	LowQuota int
+
+	mutex       sync.Mutex
+	tokens      float64
+	last        time.Time
+	scale       float64
+	pausedUntil time.Time
+}
+
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		Rate:     rate,
+		Burst:    burst,
+		LowQuota: DefaultLowQuota,
+		tokens:   float64(burst),
+		scale:    1,
+	}
+}
+
+// Wait blocks until a job can be started, or the context is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		delay := l.reserve(time.Now())
+		if delay <= 0 {
+			return nil
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// reserve takes a token if one is available, or returns how long to wait for
+// the next one.
+func (l *RateLimiter) reserve(now time.Time) time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+	if l.Rate <= 0 {
+		return 0
+	}
+
+	rate := l.Rate * l.scale
+	if !l.last.IsZero() {
+		l.tokens = math.Min(float64(l.Burst), l.tokens+now.Sub(l.last).Seconds()*rate)
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / rate * float64(time.Second))
+}
+
+// Observe adjusts the rate to the quota remaining until it is reset.
+func (l *RateLimiter) Observe(remaining int, reset time.Time) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	switch {
+	case remaining <= 0:
+		l.pausedUntil = reset
+	case remaining < l.LowQuota:
+		l.scale = float64(remaining) / float64(l.LowQuota)
+	default:
+		l.scale = 1
+	}
+}
+
+// Pause stops any jobs from starting until the time given.
+func (l *RateLimiter) Pause(until time.Time) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// RateLimitTransport reports the quota remaining in the X-RateLimit headers of
+// each response from GitHub to the rate limiter, and pauses it for as long as
+// the Retry-After header of secondary rate limit responses asks.
+type RateLimitTransport struct {
+	Base    http.RoundTripper
+	Limiter *RateLimiter
+}
+
+func (t *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err == nil {
+		reset, _ := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+		t.Limiter.Observe(remaining, time.Unix(reset, 0))
+	}
+
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		t.Limiter.Pause(time.Now().Add(time.Duration(seconds) * time.Second))
+	}
+
+	return resp, nil
+}
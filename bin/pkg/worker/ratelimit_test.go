@@ -0,0 +1,115 @@
+package worker_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jace-ys/mobydick-action/bin/pkg/worker"
+	"github.com/jace-ys/mobydick-action/bin/pkg/worker/workerfakes"
+)
+
+func TestRateLimiter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	wait := func(t *testing.T, limiter *worker.RateLimiter, times int) time.Duration {
+		start := time.Now()
+		for i := 0; i < times; i++ {
+			require.NoError(t, limiter.Wait(ctx))
+		}
+		return time.Since(start)
+	}
+
+	t.Run("Burst", func(t *testing.T) {
+		limiter := worker.NewRateLimiter(20, 2)
+
+		assert.Less(t, int64(wait(t, limiter, 2)), int64(25*time.Millisecond))
+		assert.GreaterOrEqual(t, int64(wait(t, limiter, 3)), int64(140*time.Millisecond))
+	})
+
+	t.Run("LowQuota", func(t *testing.T) {
+		limiter := worker.NewRateLimiter(100, 1)
+		limiter.LowQuota = 100
+		limiter.Observe(10, time.Now().Add(time.Hour))
+
+		assert.GreaterOrEqual(t, int64(wait(t, limiter, 3)), int64(190*time.Millisecond))
+
+		limiter.Observe(1000, time.Now().Add(time.Hour))
+		assert.Less(t, int64(wait(t, limiter, 3)), int64(60*time.Millisecond))
+	})
+
+	t.Run("QuotaExhausted", func(t *testing.T) {
+		limiter := worker.NewRateLimiter(100, 1)
+		limiter.Observe(0, time.Now().Add(time.Hour))
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+
+		assert.Error(t, limiter.Wait(timeoutCtx))
+	})
+
+	t.Run("Transport", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		}))
+		defer server.Close()
+
+		limiter := worker.NewRateLimiter(100, 1)
+		client := &http.Client{Transport: &worker.RateLimitTransport{Limiter: limiter}}
+
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+
+		assert.Error(t, limiter.Wait(timeoutCtx))
+	})
+
+	t.Run("TransportRetryAfter", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer server.Close()
+
+		limiter := worker.NewRateLimiter(100, 1)
+		client := &http.Client{Transport: &worker.RateLimitTransport{Limiter: limiter}}
+
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+
+		assert.Error(t, limiter.Wait(timeoutCtx))
+	})
+
+	t.Run("WorkerPool", func(t *testing.T) {
+		jobs := make([]worker.Job, 4)
+		for i := range jobs {
+			jobs[i] = new(workerfakes.FakeJob)
+		}
+
+		workerPool := worker.NewWorkerPool(concurrency, worker.WithRateLimiter(worker.NewRateLimiter(20, 1)))
+
+		start := time.Now()
+		results := workerPool.Work(ctx, jobs)
+		elapsed := time.Since(start)
+
+		for _, result := range results {
+			assert.NoError(t, result.Err)
+		}
+		assert.GreaterOrEqual(t, int64(elapsed), int64(140*time.Millisecond))
+	})
+}